@@ -0,0 +1,86 @@
+package resolver
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// BatchProvider is a Provider that can fetch the versions of multiple mods at once.
+// The resolver uses it to prefetch every mod that could be needed before solving
+type BatchProvider interface {
+	Provider
+
+	// ModVersionsWithDependenciesBatch returns the versions of the given mods.
+	// Mods missing from the result are fetched individually later.
+	// An error is reported for each mod of the batch the solver needs, without fetching them again
+	ModVersionsWithDependenciesBatch(ctx context.Context, modIDs []string) (map[string][]ModVersion, error)
+}
+
+const prefetchBatchSize = 50
+
+// prefetch fetches the required dependencies of the mods to install, level by level,
+// using concurrent batches for each level
+func (f *ficsitAPISource) prefetch(provider BatchProvider) {
+	seen := map[string]bool{
+		rootPkg:        true,
+		factoryGamePkg: true,
+	}
+	var frontier []string
+	for modID := range f.toInstall {
+		if seen[modID] {
+			continue
+		}
+		seen[modID] = true
+		frontier = append(frontier, modID)
+	}
+
+	for len(frontier) > 0 && f.ctx.Err() == nil {
+		slices.Sort(frontier)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var next []string
+
+		for start := 0; start < len(frontier); start += prefetchBatchSize {
+			batch := frontier[start:min(start+prefetchBatchSize, len(frontier))]
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				result, err := provider.ModVersionsWithDependenciesBatch(f.ctx, batch)
+				if err != nil {
+					mu.Lock()
+					for _, modID := range batch {
+						f.prefetchErrors[modID] = err
+					}
+					mu.Unlock()
+					return
+				}
+
+				for modID, versions := range result {
+					if _, ok := f.modVersionInfo.Load(modID); ok {
+						continue
+					}
+					versions = f.storeModVersions(modID, versions)
+
+					mu.Lock()
+					for _, version := range versions {
+						for _, dependency := range version.Dependencies {
+							if dependency.Optional || seen[dependency.ModID] {
+								continue
+							}
+							seen[dependency.ModID] = true
+							next = append(next, dependency.ModID)
+						}
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+
+		wg.Wait()
+		frontier = next
+	}
+}
@@ -0,0 +1,110 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+type batchMockProvider struct {
+	MockProvider
+	singleCalls atomic.Int32
+
+	mu      sync.Mutex
+	batches [][]string
+}
+
+func (p *batchMockProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	p.singleCalls.Add(1)
+	return p.MockProvider.ModVersionsWithDependencies(ctx, modID)
+}
+
+func (p *batchMockProvider) ModVersionsWithDependenciesBatch(ctx context.Context, modIDs []string) (map[string][]ModVersion, error) {
+	p.mu.Lock()
+	p.batches = append(p.batches, slices.Clone(modIDs))
+	p.mu.Unlock()
+
+	result := make(map[string][]ModVersion, len(modIDs))
+	for _, modID := range modIDs {
+		versions, err := p.MockProvider.ModVersionsWithDependencies(ctx, modID)
+		if err != nil {
+			continue
+		}
+		result[modID] = versions
+	}
+	return result, nil
+}
+
+func TestBatchPrefetch(t *testing.T) {
+	provider := &batchMockProvider{}
+	resolver := NewDependencyResolver(provider)
+
+	resolved, err := resolver.ResolveModDependencies(map[string]string{
+		"RefinedPower": "3.2.10",
+	}, nil, math.MaxInt, nil)
+
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, resolved.Mods, 4)
+	testza.AssertEqual(t, int32(0), provider.singleCalls.Load())
+	testza.AssertEqual(t, [][]string{
+		{"RefinedPower"},
+		{"ModularUI", "RefinedRDLib", "SML"},
+	}, provider.batches)
+}
+
+func TestBatchPrefetchMissingMod(t *testing.T) {
+	provider := &batchMockProvider{}
+	resolver := NewDependencyResolver(provider)
+
+	_, err := resolver.ResolveModDependencies(map[string]string{
+		"ThisModDoesNotExist$$$": ">0.0.0",
+	}, nil, math.MaxInt, nil)
+
+	testza.AssertEqual(t, "failed to solve dependencies: failed to make decision: failed to get package versions: failed to fetch mod ThisModDoesNotExist$$$: mod not found", err.Error())
+	testza.AssertEqual(t, int32(1), provider.singleCalls.Load())
+}
+
+func TestBatchPrefetchSkipsGame(t *testing.T) {
+	provider := &batchMockProvider{}
+	resolver := NewDependencyResolver(provider)
+
+	_, err := resolver.ResolveModDependencies(map[string]string{
+		"RefinedPower": "3.2.10",
+		"FactoryGame":  ">=264901",
+	}, nil, math.MaxInt, nil)
+
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, [][]string{
+		{"RefinedPower"},
+		{"ModularUI", "RefinedRDLib", "SML"},
+	}, provider.batches)
+}
+
+type failingBatchProvider struct {
+	batchMockProvider
+}
+
+func (p *failingBatchProvider) ModVersionsWithDependenciesBatch(context.Context, []string) (map[string][]ModVersion, error) {
+	return nil, errBadGateway
+}
+
+func TestBatchPrefetchError(t *testing.T) {
+	provider := &failingBatchProvider{}
+	resolver := NewDependencyResolver(provider)
+
+	_, err := resolver.ResolveModDependencies(map[string]string{
+		"RefinedPower": "3.2.10",
+	}, nil, math.MaxInt, nil)
+
+	var providerErr *ProviderError
+	testza.AssertTrue(t, errors.As(err, &providerErr))
+	testza.AssertEqual(t, "RefinedPower", providerErr.ModID)
+	testza.AssertErrorIs(t, err, errBadGateway)
+	testza.AssertEqual(t, int32(0), provider.singleCalls.Load())
+}
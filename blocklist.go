@@ -0,0 +1,92 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+// BlockRule forbids a mod from being installed, even as a dependency of another mod
+type BlockRule struct {
+	ModID string
+
+	// Constraint limits the rule to the matching versions, an empty constraint blocks every version
+	Constraint string
+
+	Reason string
+}
+
+func (r BlockRule) String() string {
+	result := r.ModID
+	if r.Constraint != "" {
+		result += fmt.Sprintf(" \"%s\"", r.Constraint)
+	}
+	if r.Reason != "" {
+		result += fmt.Sprintf(" (%s)", r.Reason)
+	}
+	return result
+}
+
+type blockRule struct {
+	BlockRule
+	constraint semver.Constraint
+}
+
+type blocklist map[string][]blockRule
+
+func newBlocklist(rules []BlockRule) (blocklist, error) {
+	result := make(blocklist, len(rules))
+	for _, rule := range rules {
+		if rule.ModID == "" {
+			return nil, errors.New("block rule is missing the mod id")
+		}
+		constraint := semver.AnyConstraint
+		if rule.Constraint != "" {
+			c, err := semver.NewConstraint(rule.Constraint)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse block rule constraint %s: %w", rule.Constraint, err)
+			}
+			constraint = c
+		}
+		result[rule.ModID] = append(result[rule.ModID], blockRule{BlockRule: rule, constraint: constraint})
+	}
+	return result, nil
+}
+
+func (b blocklist) isBlocked(pkg string, v semver.Version) bool {
+	for _, rule := range b[pkg] {
+		if rule.constraint.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockingRules returns the rules that block the versions of the mod in the constraint,
+// or nil if any of those versions is not blocked, since they were then excluded for another reason
+func (b blocklist) blockingRules(pkg string, constraint semver.Constraint, modVersions []ModVersion) []BlockRule {
+	var result []BlockRule
+	for _, modVersion := range modVersions {
+		v, err := semver.NewVersion(modVersion.Version)
+		if err != nil || !constraint.Contains(v) {
+			continue
+		}
+
+		blocked := false
+		for _, rule := range b[pkg] {
+			if !rule.constraint.Contains(v) {
+				continue
+			}
+			blocked = true
+			if !slices.Contains(result, rule.BlockRule) {
+				result = append(result, rule.BlockRule)
+			}
+		}
+		if !blocked {
+			return nil
+		}
+	}
+	return result
+}
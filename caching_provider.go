@@ -0,0 +1,213 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/puzpuzpuz/xsync/v3"
+)
+
+const (
+	DefaultVersionsCacheTTL = 5 * time.Minute
+	DefaultNamesCacheTTL    = time.Hour
+)
+
+type CachingProviderOptions struct {
+	// VersionsTTL is how long mod versions are cached, defaults to DefaultVersionsCacheTTL
+	VersionsTTL time.Duration
+
+	// NamesTTL is how long mod names are cached, defaults to DefaultNamesCacheTTL
+	NamesTTL time.Duration
+
+	// CacheDir persists the cache in this directory when set, so that it can be shared between processes
+	CacheDir string
+
+	Clock Clock
+}
+
+var _ SearchableProvider = (*CachingProvider)(nil)
+
+// CachingProvider caches the responses of another Provider in memory, and optionally on disk.
+// Errors are never cached
+type CachingProvider struct {
+	provider Provider
+	options  CachingProviderOptions
+	versions *xsync.MapOf[string, cacheEntry[[]ModVersion]]
+	names    *xsync.MapOf[string, cacheEntry[*ModName]]
+}
+
+type cacheEntry[T any] struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Value     T         `json:"value"`
+}
+
+const (
+	versionsCacheKind = "versions"
+	namesCacheKind    = "names"
+)
+
+func NewCachingProvider(provider Provider, options CachingProviderOptions) *CachingProvider {
+	if options.VersionsTTL == 0 {
+		options.VersionsTTL = DefaultVersionsCacheTTL
+	}
+	if options.NamesTTL == 0 {
+		options.NamesTTL = DefaultNamesCacheTTL
+	}
+	if options.Clock == nil {
+		options.Clock = systemClock{}
+	}
+	return &CachingProvider{
+		provider: provider,
+		options:  options,
+		versions: xsync.NewMapOf[string, cacheEntry[[]ModVersion]](),
+		names:    xsync.NewMapOf[string, cacheEntry[*ModName]](),
+	}
+}
+
+func (c *CachingProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	return getCached(c, versionsCacheKind, c.versions, c.options.VersionsTTL, modID, func() ([]ModVersion, error) {
+		return c.provider.ModVersionsWithDependencies(ctx, modID) //nolint:wrapcheck
+	})
+}
+
+func (c *CachingProvider) GetModName(ctx context.Context, modReference string) (*ModName, error) {
+	return getCached(c, namesCacheKind, c.names, c.options.NamesTTL, modReference, func() (*ModName, error) {
+		return c.provider.GetModName(ctx, modReference) //nolint:wrapcheck
+	})
+}
+
+// SearchMods searches the wrapped provider, the results are not cached
+func (c *CachingProvider) SearchMods(ctx context.Context, query string) ([]ModName, error) {
+	return searchMods(ctx, c.provider, query)
+}
+
+// Invalidate removes the cached data of a mod, both from memory and from disk
+func (c *CachingProvider) Invalidate(modReference string) error {
+	c.versions.Delete(modReference)
+	c.names.Delete(modReference)
+
+	if c.options.CacheDir == "" {
+		return nil
+	}
+
+	for _, kind := range []string{versionsCacheKind, namesCacheKind} {
+		if err := os.Remove(c.cachePath(kind, modReference)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove cached %s of %s: %w", kind, modReference, err)
+		}
+	}
+
+	return nil
+}
+
+// InvalidateAll removes all cached data, both from memory and from disk
+func (c *CachingProvider) InvalidateAll() error {
+	c.versions.Clear()
+	c.names.Clear()
+
+	if c.options.CacheDir == "" {
+		return nil
+	}
+
+	for _, kind := range []string{versionsCacheKind, namesCacheKind} {
+		if err := os.RemoveAll(filepath.Join(c.options.CacheDir, kind)); err != nil {
+			return fmt.Errorf("failed to remove cached %s: %w", kind, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *CachingProvider) cachePath(kind string, key string) string {
+	return filepath.Join(c.options.CacheDir, kind, url.PathEscape(key)+".json")
+}
+
+func getCached[T any](c *CachingProvider, kind string, memory *xsync.MapOf[string, cacheEntry[T]], ttl time.Duration, key string, fetch func() (T, error)) (T, error) {
+	now := c.options.Clock.Now()
+
+	if entry, ok := memory.Load(key); ok && now.Sub(entry.FetchedAt) < ttl {
+		return entry.Value, nil
+	}
+
+	if c.options.CacheDir != "" {
+		if entry, ok := readCacheEntry[T](c.cachePath(kind, key)); ok && now.Sub(entry.FetchedAt) < ttl {
+			memory.Store(key, entry)
+			return entry.Value, nil
+		}
+	}
+
+	value, err := fetch()
+	if err != nil {
+		var empty T
+		return empty, err
+	}
+
+	entry := cacheEntry[T]{
+		FetchedAt: now,
+		Value:     value,
+	}
+	memory.Store(key, entry)
+
+	if c.options.CacheDir != "" {
+		// The disk cache is only an optimization, failing to write it should not fail the request
+		_ = writeCacheEntry(c.cachePath(kind, key), entry)
+	}
+
+	return value, nil
+}
+
+func readCacheEntry[T any](path string) (cacheEntry[T], bool) {
+	var entry cacheEntry[T]
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entry, false
+	}
+
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, false
+	}
+
+	return entry, true
+}
+
+func writeCacheEntry[T any](path string, entry cacheEntry[T]) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temporary file first, so that other processes never read a partially written entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to move cache file: %w", err)
+	}
+
+	return nil
+}
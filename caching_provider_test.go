@@ -0,0 +1,144 @@
+package resolver
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Sleep advances the clock instead of blocking, and records the duration
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return nil
+}
+
+func (c *fakeClock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.sleeps)
+}
+
+type countingProvider struct {
+	Provider
+	versionCalls atomic.Int32
+	nameCalls    atomic.Int32
+}
+
+func (p *countingProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	p.versionCalls.Add(1)
+	return p.Provider.ModVersionsWithDependencies(ctx, modID) //nolint:wrapcheck
+}
+
+func (p *countingProvider) GetModName(ctx context.Context, modReference string) (*ModName, error) {
+	p.nameCalls.Add(1)
+	return p.Provider.GetModName(ctx, modReference) //nolint:wrapcheck
+}
+
+func TestCachingProviderTTL(t *testing.T) {
+	clock := newFakeClock()
+	counting := &countingProvider{Provider: MockProvider{}}
+	provider := NewCachingProvider(counting, CachingProviderOptions{
+		VersionsTTL: time.Minute,
+		NamesTTL:    time.Hour,
+		Clock:       clock,
+	})
+
+	for i := 0; i < 3; i++ {
+		versions, err := provider.ModVersionsWithDependencies(context.Background(), "SML")
+		testza.AssertNoError(t, err)
+		testza.AssertLen(t, versions, 4)
+
+		_, err = provider.GetModName(context.Background(), "SML")
+		testza.AssertNoError(t, err)
+	}
+	testza.AssertEqual(t, int32(1), counting.versionCalls.Load())
+	testza.AssertEqual(t, int32(1), counting.nameCalls.Load())
+
+	clock.Advance(2 * time.Minute)
+
+	_, _ = provider.ModVersionsWithDependencies(context.Background(), "SML")
+	_, _ = provider.GetModName(context.Background(), "SML")
+	testza.AssertEqual(t, int32(2), counting.versionCalls.Load())
+	testza.AssertEqual(t, int32(1), counting.nameCalls.Load())
+
+	testza.AssertNoError(t, provider.Invalidate("SML"))
+
+	_, _ = provider.ModVersionsWithDependencies(context.Background(), "SML")
+	_, _ = provider.GetModName(context.Background(), "SML")
+	testza.AssertEqual(t, int32(3), counting.versionCalls.Load())
+	testza.AssertEqual(t, int32(2), counting.nameCalls.Load())
+
+	// Errors are not cached
+	_, err := provider.ModVersionsWithDependencies(context.Background(), "ThisModDoesNotExist$$$")
+	testza.AssertNotNil(t, err)
+	_, err = provider.ModVersionsWithDependencies(context.Background(), "ThisModDoesNotExist$$$")
+	testza.AssertNotNil(t, err)
+	testza.AssertEqual(t, int32(5), counting.versionCalls.Load())
+}
+
+func TestCachingProviderDisk(t *testing.T) {
+	dir := t.TempDir()
+	clock := newFakeClock()
+
+	first := &countingProvider{Provider: MockProvider{}}
+	firstCache := NewCachingProvider(first, CachingProviderOptions{CacheDir: dir, Clock: clock})
+
+	expected, err := firstCache.ModVersionsWithDependencies(context.Background(), "RefinedPower")
+	testza.AssertNoError(t, err)
+
+	// A separate instance, as if in another process, shares the warm cache
+	second := &countingProvider{Provider: MockProvider{}}
+	secondCache := NewCachingProvider(second, CachingProviderOptions{CacheDir: dir, Clock: clock})
+
+	versions, err := secondCache.ModVersionsWithDependencies(context.Background(), "RefinedPower")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, expected, versions)
+	testza.AssertEqual(t, int32(0), second.versionCalls.Load())
+
+	testza.AssertNoError(t, firstCache.Invalidate("RefinedPower"))
+
+	thirdCache := NewCachingProvider(second, CachingProviderOptions{CacheDir: dir, Clock: clock})
+	_, err = thirdCache.ModVersionsWithDependencies(context.Background(), "RefinedPower")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, int32(1), second.versionCalls.Load())
+
+	clock.Advance(DefaultVersionsCacheTTL)
+
+	fourthCache := NewCachingProvider(second, CachingProviderOptions{CacheDir: dir, Clock: clock})
+	_, err = fourthCache.ModVersionsWithDependencies(context.Background(), "RefinedPower")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, int32(2), second.versionCalls.Load())
+}
@@ -0,0 +1,154 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultCircuitBreakerFailureThreshold = 5
+	DefaultCircuitBreakerCooldown         = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without calling the wrapped provider while the circuit breaker is open.
+// It is always accompanied by ErrProviderUnavailable
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures that open the circuit,
+	// defaults to DefaultCircuitBreakerFailureThreshold
+	FailureThreshold int
+
+	// Cooldown is how long the circuit stays open before a single trial request is let through,
+	// defaults to DefaultCircuitBreakerCooldown
+	Cooldown time.Duration
+
+	// IsFailure decides which errors count as failures, defaults to IsTransientError.
+	// Other errors, such as a mod not existing, show that the provider is working
+	IsFailure func(error) bool
+
+	Clock Clock
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+var _ SearchableProvider = (*CircuitBreakerProvider)(nil)
+
+// CircuitBreakerProvider stops calling another Provider after it fails repeatedly,
+// so that an unavailable provider fails fast instead of slowing down every request
+type CircuitBreakerProvider struct {
+	provider Provider
+	options  CircuitBreakerOptions
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+func NewCircuitBreakerProvider(provider Provider, options CircuitBreakerOptions) *CircuitBreakerProvider {
+	if options.FailureThreshold <= 0 {
+		options.FailureThreshold = DefaultCircuitBreakerFailureThreshold
+	}
+	if options.Cooldown == 0 {
+		options.Cooldown = DefaultCircuitBreakerCooldown
+	}
+	if options.IsFailure == nil {
+		options.IsFailure = IsTransientError
+	}
+	if options.Clock == nil {
+		options.Clock = systemClock{}
+	}
+	return &CircuitBreakerProvider{
+		provider: provider,
+		options:  options,
+	}
+}
+
+// CircuitBreaker is the ProviderMiddleware of NewCircuitBreakerProvider
+func CircuitBreaker(options CircuitBreakerOptions) ProviderMiddleware {
+	return func(provider Provider) Provider {
+		return NewCircuitBreakerProvider(provider, options)
+	}
+}
+
+func (c *CircuitBreakerProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	versions, err := c.provider.ModVersionsWithDependencies(ctx, modID)
+	c.record(err)
+	return versions, err //nolint:wrapcheck
+}
+
+func (c *CircuitBreakerProvider) GetModName(ctx context.Context, modReference string) (*ModName, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	name, err := c.provider.GetModName(ctx, modReference)
+	c.record(err)
+	return name, err //nolint:wrapcheck
+}
+
+func (c *CircuitBreakerProvider) SearchMods(ctx context.Context, query string) ([]ModName, error) {
+	if _, ok := c.provider.(SearchableProvider); !ok {
+		return nil, ErrSearchUnsupported
+	}
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	mods, err := searchMods(ctx, c.provider, query)
+	c.record(err)
+	return mods, err
+}
+
+func (c *CircuitBreakerProvider) allow() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case circuitOpen:
+		if c.options.Clock.Now().Sub(c.openedAt) < c.options.Cooldown {
+			return fmt.Errorf("%w after %d consecutive failures: %w", ErrCircuitOpen, c.failures, ErrProviderUnavailable)
+		}
+		// Let this request through as the trial, every other request keeps failing until it completes
+		c.state = circuitHalfOpen
+		return nil
+	case circuitHalfOpen:
+		return fmt.Errorf("%w while a trial request is in progress: %w", ErrCircuitOpen, ErrProviderUnavailable)
+	default:
+		return nil
+	}
+}
+
+func (c *CircuitBreakerProvider) record(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		// Says nothing about the provider, so a cancelled trial lets the next request try again
+		if c.state == circuitHalfOpen {
+			c.state = circuitOpen
+			c.openedAt = c.options.Clock.Now().Add(-c.options.Cooldown)
+		}
+	case err != nil && c.options.IsFailure(err):
+		c.failures++
+		if c.state == circuitHalfOpen || c.failures >= c.options.FailureThreshold {
+			c.state = circuitOpen
+			c.openedAt = c.options.Clock.Now()
+		}
+	default:
+		c.state = circuitClosed
+		c.failures = 0
+	}
+}
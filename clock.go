@@ -0,0 +1,36 @@
+package resolver
+
+import (
+	"context"
+	"time"
+)
+
+// Clock abstracts the passing of time, so that time dependent providers can be tested
+type Clock interface {
+	Now() time.Time
+
+	// Sleep blocks for the given duration, or until the context is done
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err() //nolint:wrapcheck
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	case <-timer.C:
+		return nil
+	}
+}
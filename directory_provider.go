@@ -0,0 +1,150 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var _ SearchableProvider = (*DirectoryProvider)(nil)
+
+// ModIndexEntry is the content of a single mod's file in a mod index directory
+type ModIndexEntry struct {
+	Mod      ModName      `json:"mod"`
+	Versions []ModVersion `json:"versions"`
+}
+
+// DirectoryProvider is a Provider that reads mods from a directory containing one <mod reference>.json file per mod
+type DirectoryProvider struct {
+	dir string
+}
+
+func NewDirectoryProvider(dir string) *DirectoryProvider {
+	return &DirectoryProvider{
+		dir: dir,
+	}
+}
+
+func (d *DirectoryProvider) ModVersionsWithDependencies(_ context.Context, modID string) ([]ModVersion, error) {
+	entry, err := d.read(modID)
+	if err != nil {
+		return nil, err
+	}
+	return entry.Versions, nil
+}
+
+func (d *DirectoryProvider) GetModName(_ context.Context, modReference string) (*ModName, error) {
+	entry, err := d.read(modReference)
+	if err != nil {
+		return nil, err
+	}
+	return &entry.Mod, nil
+}
+
+// SearchMods lists every mod in the directory, the query is only used for ranking by the caller
+func (d *DirectoryProvider) SearchMods(_ context.Context, _ string) ([]ModName, error) {
+	files, err := filepath.Glob(filepath.Join(d.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mods: %w", err)
+	}
+
+	mods := make([]ModName, 0, len(files))
+	for _, file := range files {
+		entry, err := d.read(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		mods = append(mods, entry.Mod)
+	}
+	return mods, nil
+}
+
+func (d *DirectoryProvider) read(modReference string) (*ModIndexEntry, error) {
+	path, err := modIndexPath(d.dir, modReference)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrModNotFound
+		}
+		return nil, fmt.Errorf("failed to read mod %s: %w", modReference, err)
+	}
+
+	var entry ModIndexEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse mod %s: %w", modReference, err)
+	}
+
+	return &entry, nil
+}
+
+func modIndexPath(dir string, modReference string) (string, error) {
+	if modReference == "" || modReference == "." || modReference == ".." || filepath.Base(modReference) != modReference {
+		return "", fmt.Errorf("invalid mod reference %q", modReference)
+	}
+	return filepath.Join(dir, modReference+".json"), nil
+}
+
+// ExportModIndex writes the given mods and every mod they can transitively depend on
+// from provider into dir, in the layout read by DirectoryProvider
+func ExportModIndex(ctx context.Context, provider Provider, dir string, modReferences ...string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mod index directory: %w", err)
+	}
+
+	exported := make(map[string]bool)
+	queue := slices.Clone(modReferences)
+	for len(queue) > 0 {
+		modReference := queue[0]
+		queue = queue[1:]
+
+		if exported[modReference] || modReference == factoryGamePkg {
+			continue
+		}
+		exported[modReference] = true
+
+		path, err := modIndexPath(dir, modReference)
+		if err != nil {
+			return err
+		}
+
+		versions, err := provider.ModVersionsWithDependencies(ctx, modReference)
+		if err != nil {
+			return fmt.Errorf("failed to fetch mod %s: %w", modReference, err)
+		}
+
+		name, err := provider.GetModName(ctx, modReference)
+		if err != nil {
+			return fmt.Errorf("failed to fetch name of mod %s: %w", modReference, err)
+		}
+
+		data, err := json.MarshalIndent(ModIndexEntry{
+			Mod:      *name,
+			Versions: versions,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal mod %s: %w", modReference, err)
+		}
+
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return fmt.Errorf("failed to write mod %s: %w", modReference, err)
+		}
+
+		for _, version := range versions {
+			for _, dependency := range version.Dependencies {
+				queue = append(queue, dependency.ModID)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+func TestDirectoryProvider(t *testing.T) {
+	dir := t.TempDir()
+
+	err := ExportModIndex(context.Background(), MockProvider{}, dir, "RefinedPower")
+	testza.AssertNoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	testza.AssertNoError(t, err)
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	testza.AssertEqual(t, []string{"ModularUI.json", "RefinedPower.json", "RefinedRDLib.json", "SML.json"}, names)
+
+	provider := NewDirectoryProvider(dir)
+
+	expected, _ := MockProvider{}.ModVersionsWithDependencies(context.Background(), "RefinedPower")
+	versions, err := provider.ModVersionsWithDependencies(context.Background(), "RefinedPower")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, expected, versions)
+
+	name, err := provider.GetModName(context.Background(), "SML")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "Satisfactory Mod Loader", name.Name)
+
+	_, err = provider.ModVersionsWithDependencies(context.Background(), "ComplexMod")
+	testza.AssertEqual(t, "mod not found", err.Error())
+	testza.AssertTrue(t, errors.Is(err, ErrModNotFound))
+
+	_, err = provider.ModVersionsWithDependencies(context.Background(), filepath.Join("..", "SML"))
+	testza.AssertNotNil(t, err)
+
+	resolved, err := NewDependencyResolver(provider).ResolveModDependencies(map[string]string{
+		"RefinedPower": "3.2.10",
+	}, nil, math.MaxInt, nil)
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, resolved.Mods, 4)
+}
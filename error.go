@@ -2,25 +2,64 @@ package resolver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/mircearoata/pubgrub-go/pubgrub"
 	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/puzpuzpuz/xsync/v3"
 )
 
+var (
+	// ErrModNotFound is returned by providers for mods that do not exist.
+	// Providers return it unwrapped, the resolver names the mod in a ProviderError
+	ErrModNotFound = errors.New("mod not found")
+
+	// ErrProviderUnavailable is returned by providers that could not be reached,
+	// so it is unknown whether the mod exists
+	ErrProviderUnavailable = errors.New("provider unavailable")
+)
+
+// ProviderError is returned by the resolver when the provider fails to fetch a mod
+type ProviderError struct {
+	ModID string
+	Err   error
+
+	// Suggestions are the closest known mod references, if the mod was not found and the provider is a SearchableProvider
+	Suggestions []string
+}
+
+func (e *ProviderError) Error() string {
+	message := fmt.Sprintf("failed to fetch mod %s: %v", e.ModID, e.Err)
+	if len(e.Suggestions) > 0 {
+		message += fmt.Sprintf(" (did you mean %s?)", strings.Join(e.Suggestions, ", "))
+	}
+	return message
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
 type DependencyResolverError struct {
 	pubgrub.SolvingError
+	// ctx is detached from the cancellation of the resolve, since the error can be rendered after it is done
+	ctx         context.Context
 	provider    Provider
 	gameVersion int
+	blocklist   blocklist
+	modVersions *xsync.MapOf[string, []ModVersion]
 }
 
 func (e DependencyResolverError) Error() string {
 	rootPkg := e.Cause().Terms()[0].Dependency()
 
-	writer := pubgrub.NewStandardErrorWriter(rootPkg).WithIncompatibilityStringer(
-		MakeDependencyResolverErrorStringer(e.provider, e.gameVersion),
-	)
+	stringer := MakeDependencyResolverErrorStringerContext(e.ctx, e.provider, e.gameVersion)
+	stringer.blocklist = e.blocklist
+	stringer.modVersions = e.modVersions
+
+	writer := pubgrub.NewStandardErrorWriter(rootPkg).WithIncompatibilityStringer(stringer)
 	e.WriteTo(writer)
 
 	return writer.String()
@@ -28,42 +67,66 @@ func (e DependencyResolverError) Error() string {
 
 type DependencyResolverErrorStringer struct {
 	pubgrub.StandardIncompatibilityStringer
+	ctx          context.Context
 	provider     Provider
-	packageNames map[string]string
+	packageNames *packageNames
 	gameVersion  int
+	blocklist    blocklist
+
+	// modVersions are the versions the solver saw, after dependency overrides were applied
+	modVersions *xsync.MapOf[string, []ModVersion]
 }
 
 func MakeDependencyResolverErrorStringer(provider Provider, gameVersion int) *DependencyResolverErrorStringer {
+	return MakeDependencyResolverErrorStringerContext(context.Background(), provider, gameVersion)
+}
+
+// MakeDependencyResolverErrorStringerContext creates a stringer that looks up mod names and versions with ctx
+func MakeDependencyResolverErrorStringerContext(ctx context.Context, provider Provider, gameVersion int) *DependencyResolverErrorStringer {
 	s := &DependencyResolverErrorStringer{
+		ctx:          ctx,
 		provider:     provider,
 		gameVersion:  gameVersion,
-		packageNames: map[string]string{},
+		packageNames: newPackageNames(provider),
 	}
 	s.StandardIncompatibilityStringer = pubgrub.NewStandardIncompatibilityStringer().WithTermStringer(s)
 	return s
 }
 
-func (w *DependencyResolverErrorStringer) getPackageName(pkg string) string {
+// packageNames looks up and caches the display names of packages
+type packageNames struct {
+	provider Provider
+	names    map[string]string
+}
+
+func newPackageNames(provider Provider) *packageNames {
+	return &packageNames{
+		provider: provider,
+		names:    map[string]string{},
+	}
+}
+
+func (p *packageNames) get(ctx context.Context, pkg string) string {
 	if pkg == factoryGamePkg {
 		return "Satisfactory"
 	}
 
-	if name, ok := w.packageNames[pkg]; ok {
+	if name, ok := p.names[pkg]; ok {
 		return name
 	}
 
-	result, err := w.provider.GetModName(context.TODO(), pkg)
+	result, err := p.provider.GetModName(ctx, pkg)
 	if err != nil {
 		return pkg
 	}
 
-	w.packageNames[pkg] = result.Name
+	p.names[pkg] = result.Name
 
 	return result.Name
 }
 
 func (w *DependencyResolverErrorStringer) Term(t pubgrub.Term, includeVersion bool) string {
-	name := w.getPackageName(t.Dependency())
+	name := w.packageNames.get(w.ctx, t.Dependency())
 	fullName := fmt.Sprintf("%s (%s)", name, t.Dependency())
 	if name == t.Dependency() {
 		fullName = t.Dependency()
@@ -79,7 +142,7 @@ func (w *DependencyResolverErrorStringer) Term(t pubgrub.Term, includeVersion bo
 			// Remove ".0.0" from the versions mentioned, since only the major is ever used
 			return fmt.Sprintf("%s \"%s\"", fullName, strings.ReplaceAll(t.Constraint().String(), ".0.0", ""))
 		default:
-			res, err := w.provider.ModVersionsWithDependencies(context.TODO(), t.Dependency())
+			res, err := w.provider.ModVersionsWithDependencies(w.ctx, t.Dependency())
 			if err != nil {
 				return fmt.Sprintf("%s \"%s\"", fullName, t.Constraint())
 			}
@@ -116,5 +179,102 @@ func (w *DependencyResolverErrorStringer) IncompatibilityString(incompatibility
 		return fmt.Sprintf("Satisfactory CL%d is installed", w.gameVersion)
 	}
 
+	if len(terms) == 1 && terms[0].Positive() && len(w.blocklist[terms[0].Dependency()]) > 0 {
+		if rules := w.blocklist.blockingRules(terms[0].Dependency(), terms[0].Constraint(), w.versions(terms[0].Dependency())); len(rules) > 0 {
+			ruleStrings := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				ruleStrings = append(ruleStrings, rule.String())
+			}
+			return fmt.Sprintf("%s is blocked by rule %s", w.Term(terms[0], true), strings.Join(ruleStrings, ", "))
+		}
+	}
+
+	if len(terms) == 2 && terms[0].Positive() && terms[1].Positive() {
+		mod, other := terms[0], terms[1]
+		if mod.Dependency() > other.Dependency() {
+			mod, other = other, mod
+		}
+		if other.Dependency() == rootPkg {
+			mod, other = other, mod
+		}
+		if mod.Dependency() == rootPkg && w.derivesFromConflict(incompatibility) {
+			// Installing the other package at all would be a conflict
+			return fmt.Sprintf("%s is forbidden", w.Term(other, true))
+		}
+		if w.declaresIncompatibility(mod, other) {
+			return fmt.Sprintf("%s conflicts with %s", w.Term(mod, true), w.Term(other, true))
+		}
+		if w.declaresIncompatibility(other, mod) {
+			return fmt.Sprintf("%s conflicts with %s", w.Term(other, true), w.Term(mod, true))
+		}
+	}
+
 	return w.StandardIncompatibilityStringer.IncompatibilityString(incompatibility, rootPkg)
 }
+
+// versions returns the versions of a mod the solver saw, or those of the provider if they are not known
+func (w *DependencyResolverErrorStringer) versions(pkg string) []ModVersion {
+	if pkg == rootPkg || pkg == factoryGamePkg {
+		return nil
+	}
+
+	if w.modVersions != nil {
+		if versions, ok := w.modVersions.Load(pkg); ok {
+			return versions
+		}
+	}
+
+	versions, err := w.provider.ModVersionsWithDependencies(w.ctx, pkg)
+	if err != nil {
+		return nil
+	}
+	return versions
+}
+
+// derivesFromConflict checks if the incompatibility was derived from a conflict declared in ModVersion.Incompatibilities
+func (w *DependencyResolverErrorStringer) derivesFromConflict(incompatibility *pubgrub.Incompatibility) bool {
+	causes := incompatibility.Causes()
+	if len(causes) == 0 {
+		terms := incompatibility.Terms()
+		if len(terms) != 2 || !terms[0].Positive() || !terms[1].Positive() {
+			return false
+		}
+		return w.declaresIncompatibility(terms[0], terms[1]) || w.declaresIncompatibility(terms[1], terms[0])
+	}
+
+	for _, cause := range causes {
+		if w.derivesFromConflict(cause) {
+			return true
+		}
+	}
+	return false
+}
+
+// declaresIncompatibility checks if any version of mod the solver saw declares an incompatibility with the versions of other
+func (w *DependencyResolverErrorStringer) declaresIncompatibility(mod pubgrub.Term, other pubgrub.Term) bool {
+	for _, v := range w.versions(mod.Dependency()) {
+		ver, err := semver.NewVersion(v.Version)
+		if err != nil || !mod.Constraint().Contains(ver) {
+			continue
+		}
+
+		for _, incompatibility := range v.Incompatibilities {
+			if incompatibility.ModID != other.Dependency() {
+				continue
+			}
+			condition := incompatibility.Condition
+			if condition == "" {
+				condition = "*"
+			}
+			c, err := semver.NewConstraint(condition)
+			if err != nil {
+				continue
+			}
+			if !c.Intersect(other.Constraint()).IsEmpty() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DependencyPath is a chain of dependencies from a requested constraint to a mod
+type DependencyPath []DependencyStep
+
+type DependencyStep struct {
+	ModReference string
+	Name         string
+	Version      string
+
+	// RequiredBy is the mod reference of the dependant, or empty for a requested constraint
+	RequiredBy string
+	Constraint string
+	Optional   bool
+}
+
+func (s DependencyStep) String() string {
+	fullName := fmt.Sprintf("%s (%s)", s.Name, s.ModReference)
+	if s.Name == s.ModReference || s.Name == "" {
+		fullName = s.ModReference
+	}
+
+	via := "requested"
+	if s.RequiredBy != "" {
+		via = "required"
+		if s.Optional {
+			via = "optionally required"
+		}
+	}
+
+	return fmt.Sprintf("%s \"%s\" (%s as \"%s\")", fullName, s.Version, via, s.Constraint)
+}
+
+func (p DependencyPath) String() string {
+	steps := make([]string, 0, len(p))
+	for _, step := range p {
+		steps = append(steps, step.String())
+	}
+	return strings.Join(steps, " -> ")
+}
+
+// Why returns every dependency path from the requested constraints to the given mod
+func (r *ResolveResult) Why(ctx context.Context, modReference string) ([]DependencyPath, error) {
+	if _, ok := r.Mods[modReference]; !ok {
+		return nil, fmt.Errorf("mod %s is not part of the resolved mods", modReference)
+	}
+
+	var names *packageNames
+	if r.provider != nil {
+		names = newPackageNames(r.provider)
+	}
+
+	makeStep := func(edge ResolvedDependency) DependencyStep {
+		name := edge.To
+		if names != nil {
+			name = names.get(ctx, edge.To)
+		}
+		return DependencyStep{
+			ModReference: edge.To,
+			Name:         name,
+			Version:      r.Mods[edge.To].Version,
+			RequiredBy:   edge.From,
+			Constraint:   edge.Constraint,
+			Optional:     edge.Optional,
+		}
+	}
+
+	var paths []DependencyPath
+	onPath := map[string]bool{}
+
+	var walk func(edge ResolvedDependency, path DependencyPath)
+	walk = func(edge ResolvedDependency, path DependencyPath) {
+		if onPath[edge.To] {
+			return
+		}
+
+		path = append(path, makeStep(edge))
+		if edge.To == modReference {
+			paths = append(paths, append(DependencyPath{}, path...))
+			return
+		}
+
+		onPath[edge.To] = true
+		for _, dependency := range r.Mods[edge.To].Dependencies {
+			walk(dependency, path)
+		}
+		delete(onPath, edge.To)
+	}
+
+	for _, root := range r.Roots {
+		if _, ok := r.Mods[root.To]; !ok {
+			continue
+		}
+		walk(root, nil)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,252 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const DefaultGraphQLEndpoint = "https://api.ficsit.app/v2/query"
+
+// graphQLVersionsPageSize is the maximum page size the API allows for mod versions
+const graphQLVersionsPageSize = 100
+
+const modVersionsWithDependenciesQuery = `query ModVersionsWithDependencies($modId: String!, $limit: Int!, $offset: Int!) {
+	mod: getModByIdOrReference(modIdOrReference: $modId) {
+		id
+		versions(filter: {limit: $limit, offset: $offset}) {
+			version
+			game_version
+			required_on_remote
+			dependencies {
+				mod_id
+				condition
+				optional
+			}
+			targets {
+				targetName
+				link
+				hash
+				size
+			}
+		}
+	}
+}`
+
+const getModNameQuery = `query GetModName($modReference: ModReference!) {
+	mod: getModByReference(modReference: $modReference) {
+		id
+		mod_reference
+		name
+	}
+}`
+
+var _ Provider = (*GraphQLProvider)(nil)
+
+// GraphQLProvider is a Provider backed by the ficsit.app GraphQL API
+type GraphQLProvider struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewGraphQLProvider creates a provider for the given endpoint, or DefaultGraphQLEndpoint if empty.
+// A nil client uses http.DefaultClient
+func NewGraphQLProvider(endpoint string, client *http.Client) *GraphQLProvider {
+	if endpoint == "" {
+		endpoint = DefaultGraphQLEndpoint
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &GraphQLProvider{
+		endpoint: endpoint,
+		client:   client,
+	}
+}
+
+// HTTPStatusError is returned when the API responds with an unexpected status code
+type HTTPStatusError struct {
+	StatusCode int
+	Operation  string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d for %s request", e.StatusCode, e.Operation)
+}
+
+// Unwrap returns ErrProviderUnavailable for the status codes of an overloaded or unreachable API
+func (e *HTTPStatusError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return ErrProviderUnavailable
+	}
+	return nil
+}
+
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphQLError  `json:"errors"`
+}
+
+type graphQLModVersionsResponse struct {
+	Mod *struct {
+		ID       string `json:"id"`
+		Versions []struct {
+			Version          string       `json:"version"`
+			GameVersion      string       `json:"game_version"`
+			RequiredOnRemote bool         `json:"required_on_remote"`
+			Dependencies     []Dependency `json:"dependencies"`
+			Targets          []struct {
+				TargetName TargetName `json:"targetName"`
+				Link       string     `json:"link"`
+				Hash       string     `json:"hash"`
+				Size       int64      `json:"size"`
+			} `json:"targets"`
+		} `json:"versions"`
+	} `json:"mod"`
+}
+
+type graphQLModNameResponse struct {
+	Mod *ModName `json:"mod"`
+}
+
+func (g *GraphQLProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	var result []ModVersion
+	for offset := 0; ; offset += graphQLVersionsPageSize {
+		var response graphQLModVersionsResponse
+		err := g.query(ctx, "ModVersionsWithDependencies", modVersionsWithDependenciesQuery, map[string]any{
+			"modId":  modID,
+			"limit":  graphQLVersionsPageSize,
+			"offset": offset,
+		}, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.Mod == nil {
+			return nil, ErrModNotFound
+		}
+
+		for _, version := range response.Mod.Versions {
+			targets := make([]Target, 0, len(version.Targets))
+			for _, target := range version.Targets {
+				link, err := g.resolveLink(target.Link)
+				if err != nil {
+					return nil, err
+				}
+				targets = append(targets, Target{
+					TargetName: target.TargetName,
+					Link:       link,
+					Hash:       target.Hash,
+					Size:       target.Size,
+				})
+			}
+
+			result = append(result, ModVersion{
+				Version:          version.Version,
+				GameVersion:      version.GameVersion,
+				Dependencies:     version.Dependencies,
+				Targets:          targets,
+				RequiredOnRemote: version.RequiredOnRemote,
+			})
+		}
+
+		if len(response.Mod.Versions) < graphQLVersionsPageSize {
+			return result, nil
+		}
+	}
+}
+
+func (g *GraphQLProvider) GetModName(ctx context.Context, modReference string) (*ModName, error) {
+	var response graphQLModNameResponse
+	err := g.query(ctx, "GetModName", getModNameQuery, map[string]any{
+		"modReference": modReference,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Mod == nil {
+		return nil, ErrModNotFound
+	}
+
+	return response.Mod, nil
+}
+
+// resolveLink makes the download links returned by the API, which are relative to the API host, absolute
+func (g *GraphQLProvider) resolveLink(link string) (string, error) {
+	if link == "" {
+		return "", nil
+	}
+	base, err := url.Parse(g.endpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse endpoint %s: %w", g.endpoint, err)
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse link %s: %w", link, err)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+func (g *GraphQLProvider) query(ctx context.Context, operationName string, query string, variables map[string]any, out any) error {
+	body, err := json.Marshal(graphQLRequest{
+		Query:         query,
+		OperationName: operationName,
+		Variables:     variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", operationName, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", operationName, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to execute %s request: %w", operationName, err)
+		}
+		return fmt.Errorf("%w: failed to execute %s request: %w", ErrProviderUnavailable, operationName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			Operation:  operationName,
+		}
+	}
+
+	var response graphQLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", operationName, err)
+	}
+
+	if len(response.Errors) > 0 {
+		return fmt.Errorf("%s request failed: %s", operationName, response.Errors[0].Message)
+	}
+
+	if err := json.Unmarshal(response.Data, out); err != nil {
+		return fmt.Errorf("failed to decode %s response data: %w", operationName, err)
+	}
+
+	return nil
+}
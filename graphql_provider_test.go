@@ -0,0 +1,100 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+// newRecordedGraphQLServer serves the recorded responses in testdata/graphql,
+// named after the operation and the mod it queries
+func newRecordedGraphQLServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var mod any
+		switch request.OperationName {
+		case "ModVersionsWithDependencies":
+			mod = request.Variables["modId"]
+			if request.Variables["offset"] != float64(0) {
+				_, _ = w.Write([]byte(`{"data": {"mod": {"id": "", "versions": []}}}`))
+				return
+			}
+		case "GetModName":
+			mod = request.Variables["modReference"]
+		}
+
+		response, err := os.ReadFile(filepath.Join("testdata", "graphql", request.OperationName+"_"+mod.(string)+".json"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestGraphQLProvider(t *testing.T) {
+	server := newRecordedGraphQLServer(t)
+	provider := NewGraphQLProvider(server.URL+"/v2/query", server.Client())
+
+	versions, err := provider.ModVersionsWithDependencies(context.Background(), "SML")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, versions, 2)
+	testza.AssertEqual(t, "3.6.1", versions[0].Version)
+	testza.AssertEqual(t, ">=264901", versions[0].GameVersion)
+	testza.AssertTrue(t, versions[0].RequiredOnRemote)
+	testza.AssertEqual(t, Target{
+		TargetName: TargetNameLinuxServer,
+		Link:       server.URL + "/v1/version/8eHrwuKjHDqU2j/LinuxServer/download",
+		Hash:       "0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f9",
+		Size:       22871652,
+	}, versions[0].Targets[2])
+
+	name, err := provider.GetModName(context.Background(), "RefinedPower")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, &ModName{
+		ID:           "DGiLzB3ZErWu2V",
+		ModReference: "RefinedPower",
+		Name:         "Refined Power",
+	}, name)
+
+	_, err = provider.ModVersionsWithDependencies(context.Background(), "ThisModDoesNotExist")
+	testza.AssertEqual(t, "mod not found", err.Error())
+	testza.AssertTrue(t, errors.Is(err, ErrModNotFound))
+
+	_, err = provider.GetModName(context.Background(), "NotRecorded")
+	testza.AssertEqual(t, "unexpected status code 404 for GetModName request", err.Error())
+}
+
+func TestGraphQLProviderResolution(t *testing.T) {
+	server := newRecordedGraphQLServer(t)
+	resolver := NewDependencyResolver(NewGraphQLProvider(server.URL+"/v2/query", server.Client()))
+
+	resolved, err := resolver.ResolveModDependencies(map[string]string{
+		"RefinedPower": "*",
+	}, nil, math.MaxInt, []TargetName{TargetNameWindows})
+
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, resolved.Mods, 2)
+	testza.AssertEqual(t, "3.6.1", resolved.Mods["SML"].Version)
+	testza.AssertEqual(t, server.URL+"/v1/version/7p3Hq6ZqmdEXtr/Windows/download", resolved.Mods["RefinedPower"].Targets["Windows"].Link)
+}
@@ -0,0 +1,151 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/puzpuzpuz/xsync/v3"
+)
+
+type LayerMode int
+
+const (
+	// LayerShadow replaces the versions of the lower layers for every mod the layer provides
+	LayerShadow LayerMode = iota
+
+	// LayerMerge adds the versions of the layer next to those of the lower layers.
+	// If a version exists in multiple layers, the highest priority layer provides it
+	LayerMerge
+)
+
+type ProviderLayer struct {
+	Name     string
+	Provider Provider
+	Mode     LayerMode
+}
+
+var _ SearchableProvider = (*LayeredProvider)(nil)
+
+// LayeredProvider queries multiple providers in priority order,
+// e.g. to resolve against local development builds of some mods, and a remote registry for the rest.
+// Layers returning ErrModNotFound are skipped, any other error fails the request
+type LayeredProvider struct {
+	layers []ProviderLayer
+
+	// sources maps mod references to the layer name each version came from
+	sources *xsync.MapOf[string, map[string]string]
+}
+
+// NewLayeredProvider creates a provider from the given layers, the first one having the highest priority
+func NewLayeredProvider(layers ...ProviderLayer) *LayeredProvider {
+	return &LayeredProvider{
+		layers:  layers,
+		sources: xsync.NewMapOf[string, map[string]string](),
+	}
+}
+
+func (l *LayeredProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	var result []ModVersion
+	sources := make(map[string]string)
+
+	for _, layer := range l.layers {
+		versions, err := layer.Provider.ModVersionsWithDependencies(ctx, modID)
+		if errors.Is(err, ErrModNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("layer %s: %w", layer.Name, err)
+		}
+		if len(versions) == 0 {
+			continue
+		}
+
+		for _, version := range versions {
+			if _, ok := sources[version.Version]; ok {
+				continue
+			}
+			sources[version.Version] = layer.Name
+			result = append(result, version)
+		}
+
+		if layer.Mode == LayerShadow {
+			break
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, ErrModNotFound
+	}
+
+	l.sources.Store(modID, sources)
+
+	return result, nil
+}
+
+func (l *LayeredProvider) GetModName(ctx context.Context, modReference string) (*ModName, error) {
+	for _, layer := range l.layers {
+		name, err := layer.Provider.GetModName(ctx, modReference)
+		if errors.Is(err, ErrModNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("layer %s: %w", layer.Name, err)
+		}
+		return name, nil
+	}
+	return nil, ErrModNotFound
+}
+
+// SearchMods searches every layer that is a SearchableProvider, the highest priority layer providing each mod
+func (l *LayeredProvider) SearchMods(ctx context.Context, query string) ([]ModName, error) {
+	var result []ModName
+	seen := make(map[string]bool)
+	searched := false
+
+	for _, layer := range l.layers {
+		mods, err := searchMods(ctx, layer.Provider, query)
+		if errors.Is(err, ErrSearchUnsupported) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("layer %s: %w", layer.Name, err)
+		}
+		searched = true
+
+		for _, mod := range mods {
+			if seen[mod.ModReference] {
+				continue
+			}
+			seen[mod.ModReference] = true
+			result = append(result, mod)
+		}
+	}
+
+	if !searched {
+		return nil, ErrSearchUnsupported
+	}
+	return result, nil
+}
+
+// VersionSource returns the name of the layer a version of a mod was provided by,
+// for the versions returned by the latest ModVersionsWithDependencies call for the mod
+func (l *LayeredProvider) VersionSource(modReference string, version string) (string, bool) {
+	sources, ok := l.sources.Load(modReference)
+	if !ok {
+		return "", false
+	}
+	source, ok := sources[version]
+	return source, ok
+}
+
+// Sources returns the layer name each resolved mod version came from
+func (l *LayeredProvider) Sources(result *ResolveResult) map[string]string {
+	sources := make(map[string]string, len(result.Mods))
+	for modReference, mod := range result.Mods {
+		if source, ok := l.VersionSource(modReference, mod.ModVersion.Version); ok {
+			sources[modReference] = source
+		}
+	}
+	return sources
+}
@@ -0,0 +1,107 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+var localRefinedPower = staticProvider{
+	versions: map[string][]ModVersion{
+		"RefinedPower": {
+			{
+				Version:          "3.3.0",
+				RequiredOnRemote: true,
+				Dependencies: []Dependency{
+					{ModID: "SML", Condition: "^3.6.0"},
+				},
+				Targets: commonTargets,
+			},
+			{
+				Version:          "3.2.13",
+				RequiredOnRemote: true,
+				Dependencies: []Dependency{
+					{ModID: "SML", Condition: "^3.6.0"},
+				},
+				Targets: commonTargets,
+			},
+		},
+	},
+	names: map[string]ModName{
+		"RefinedPower": {ID: "local", ModReference: "RefinedPower", Name: "Refined Power (dev)"},
+	},
+}
+
+func TestLayeredProviderShadow(t *testing.T) {
+	provider := NewLayeredProvider(
+		ProviderLayer{Name: "local", Provider: localRefinedPower, Mode: LayerShadow},
+		ProviderLayer{Name: "remote", Provider: MockProvider{}},
+	)
+
+	versions, err := provider.ModVersionsWithDependencies(context.Background(), "RefinedPower")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, versions, 2)
+
+	versions, err = provider.ModVersionsWithDependencies(context.Background(), "SML")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, versions, 4)
+
+	name, err := provider.GetModName(context.Background(), "RefinedPower")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "Refined Power (dev)", name.Name)
+
+	result, err := NewDependencyResolver(provider).Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": "*",
+	}, WithGameVersion(math.MaxInt)))
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, result.Mods, 2)
+	testza.AssertEqual(t, map[string]string{
+		"RefinedPower": "local",
+		"SML":          "remote",
+	}, provider.Sources(result))
+}
+
+func TestLayeredProviderMerge(t *testing.T) {
+	provider := NewLayeredProvider(
+		ProviderLayer{Name: "local", Provider: localRefinedPower, Mode: LayerMerge},
+		ProviderLayer{Name: "remote", Provider: MockProvider{}},
+	)
+
+	versions, err := provider.ModVersionsWithDependencies(context.Background(), "RefinedPower")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, versions, 4)
+
+	source, ok := provider.VersionSource("RefinedPower", "3.2.13")
+	testza.AssertTrue(t, ok)
+	testza.AssertEqual(t, "local", source)
+
+	source, ok = provider.VersionSource("RefinedPower", "3.2.11")
+	testza.AssertTrue(t, ok)
+	testza.AssertEqual(t, "remote", source)
+
+	result, err := NewDependencyResolver(provider).Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": "<3.2.13",
+	}, WithGameVersion(math.MaxInt)))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.2.11", result.LockFile.Mods["RefinedPower"].Version)
+	testza.AssertEqual(t, "remote", provider.Sources(result)["RefinedPower"])
+}
+
+func TestLayeredProviderErrors(t *testing.T) {
+	provider := NewLayeredProvider(
+		ProviderLayer{Name: "local", Provider: localRefinedPower},
+		ProviderLayer{Name: "remote", Provider: newFlakyProvider(1, errBadGateway)},
+	)
+
+	// Only a missing mod falls through to the next layer
+	_, err := provider.ModVersionsWithDependencies(context.Background(), "SML")
+	testza.AssertEqual(t, "layer remote: unexpected status code 502 for ModVersionsWithDependencies request", err.Error())
+	testza.AssertTrue(t, errors.Is(err, ErrProviderUnavailable))
+
+	_, err = provider.ModVersionsWithDependencies(context.Background(), "ThisModDoesNotExist$$$")
+	testza.AssertEqual(t, "mod not found", err.Error())
+	testza.AssertTrue(t, errors.Is(err, ErrModNotFound))
+}
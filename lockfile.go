@@ -1,5 +1,14 @@
 package resolver
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+)
+
 type LockfileVersion int
 
 const (
@@ -7,25 +16,55 @@ const (
 
 	ModTargetsLockfileVersion
 
+	// ModMetadataLockfileVersion records the size of the targets, and whether the mod is required on remote and its game version
+	ModMetadataLockfileVersion
+
 	// Always last
 	nextLockfileVersion
 	CurrentLockfileVersion = nextLockfileVersion - 1
 )
 
+// ErrUnsupportedLockfileVersion is returned when reading a lockfile written by a newer version of the library
+var ErrUnsupportedLockfileVersion = errors.New("unsupported lockfile version")
+
 type LockFile struct {
-	Mods    map[string]LockedMod `json:"mods"`
-	Version LockfileVersion      `json:"version"`
+	Mods      map[string]LockedMod      `json:"mods"`
+	Overrides map[string]LockedOverride `json:"overrides,omitempty"`
+	Version   LockfileVersion           `json:"version"`
 }
 
 type LockedMod struct {
-	Dependencies map[string]string          `json:"dependencies"`
-	Targets      map[string]LockedModTarget `json:"targets"`
-	Version      string                     `json:"version"`
+	Dependencies     map[string]string          `json:"dependencies"`
+	Targets          map[string]LockedModTarget `json:"targets"`
+	Version          string                     `json:"version"`
+	RequiredOnRemote bool                       `json:"required_on_remote"`
+
+	// GameVersion is the game version constraint of the locked version, empty if unknown
+	GameVersion string `json:"game_version,omitempty"`
+}
+
+type LockedOverride struct {
+	Condition   string `json:"condition,omitempty"`
+	ReplaceWith string `json:"replace_with,omitempty"`
+}
+
+func (o LockedOverride) String() string {
+	switch {
+	case o.ReplaceWith == "":
+		return o.Condition
+	case o.Condition == "":
+		return "replaced with " + o.ReplaceWith
+	default:
+		return fmt.Sprintf("replaced with %s %s", o.ReplaceWith, o.Condition)
+	}
 }
 
 type LockedModTarget struct {
 	Hash string `json:"hash"`
 	Link string `json:"link"`
+
+	// Size is the size of the artifact in bytes, zero if unknown
+	Size int64 `json:"size,omitempty"`
 }
 
 func NewLockfile() *LockFile {
@@ -35,20 +74,125 @@ func NewLockfile() *LockFile {
 	}
 }
 
+// ReadLockfile reads a lockfile of any supported version, migrating it to CurrentLockfileVersion
+func ReadLockfile(r io.Reader) (*LockFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+
+	version, err := lockfileVersion(data)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentLockfileVersion || version < InitialLockfileVersion {
+		return nil, fmt.Errorf("%w %d, the latest supported version is %d", ErrUnsupportedLockfileVersion, version, CurrentLockfileVersion)
+	}
+
+	data, err = migrateLockfile(data, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var lockFile LockFile
+	if err := json.Unmarshal(data, &lockFile); err != nil {
+		return nil, fmt.Errorf("failed to decode lockfile: %w", err)
+	}
+	if lockFile.Mods == nil {
+		lockFile.Mods = make(map[string]LockedMod)
+	}
+	lockFile.Version = CurrentLockfileVersion
+
+	return &lockFile, nil
+}
+
+// WriteTo writes the lockfile as indented JSON with sorted keys,
+// so that the same lockfile always produces the same bytes
+func (l *LockFile) WriteTo(w io.Writer) (int64, error) {
+	lockFile := *l
+	if lockFile.Mods == nil {
+		lockFile.Mods = make(map[string]LockedMod)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(lockFile); err != nil {
+		return 0, fmt.Errorf("failed to encode lockfile: %w", err)
+	}
+
+	n, err := w.Write(buf.Bytes())
+	if err != nil {
+		return int64(n), fmt.Errorf("failed to write lockfile: %w", err)
+	}
+	return int64(n), nil
+}
+
+// Clone returns a deep copy of the lockfile, that can be modified without affecting the original
 func (l *LockFile) Clone() *LockFile {
 	lockFile := &LockFile{
-		Mods:    make(map[string]LockedMod),
+		Mods:    make(map[string]LockedMod, len(l.Mods)),
 		Version: l.Version,
 	}
 	for k, v := range l.Mods {
-		lockFile.Mods[k] = v
+		lockFile.Mods[k] = v.Clone()
+	}
+	if l.Overrides != nil {
+		lockFile.Overrides = maps.Clone(l.Overrides)
 	}
 	return lockFile
 }
 
+// Remove deletes the mods from the lockfile in place, and returns the same lockfile for chaining.
+// Use Without to get a copy without the mods instead
 func (l *LockFile) Remove(modID ...string) *LockFile {
 	for _, s := range modID {
 		delete(l.Mods, s)
 	}
 	return l
 }
+
+// With returns a copy of the lockfile with the mod added or replaced
+func (l *LockFile) With(modReference string, mod LockedMod) *LockFile {
+	lockFile := l.Clone()
+	lockFile.Mods[modReference] = mod.Clone()
+	return lockFile
+}
+
+// Without returns a copy of the lockfile without the given mods
+func (l *LockFile) Without(modReferences ...string) *LockFile {
+	return l.Clone().Remove(modReferences...)
+}
+
+// Filter returns a copy of the lockfile with only the mods keep returns true for
+func (l *LockFile) Filter(keep func(modReference string, mod LockedMod) bool) *LockFile {
+	lockFile := l.Clone()
+	maps.DeleteFunc(lockFile.Mods, func(modReference string, mod LockedMod) bool {
+		return !keep(modReference, mod)
+	})
+	return lockFile
+}
+
+// Merge returns a copy of the lockfile with the mods and overrides of other added.
+// Mods and overrides present in both lockfiles are taken from other
+func (l *LockFile) Merge(other *LockFile) *LockFile {
+	lockFile := l.Clone()
+	for k, v := range other.Mods {
+		lockFile.Mods[k] = v.Clone()
+	}
+	if other.Overrides != nil {
+		if lockFile.Overrides == nil {
+			lockFile.Overrides = make(map[string]LockedOverride, len(other.Overrides))
+		}
+		maps.Copy(lockFile.Overrides, other.Overrides)
+	}
+	return lockFile
+}
+
+// Clone returns a copy of the locked mod that does not share its maps with the original
+func (m LockedMod) Clone() LockedMod {
+	m.Dependencies = maps.Clone(m.Dependencies)
+	m.Targets = maps.Clone(m.Targets)
+	return m
+}
@@ -0,0 +1,280 @@
+package resolver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+type ModChangeKind string
+
+const (
+	ModAdded      ModChangeKind = "added"
+	ModRemoved    ModChangeKind = "removed"
+	ModUpgraded   ModChangeKind = "upgraded"
+	ModDowngraded ModChangeKind = "downgraded"
+
+	// ModRetargeted is a mod with the same version, but a different set of targets
+	ModRetargeted ModChangeKind = "retargeted"
+
+	// ModHashChanged is a mod with the same version and targets, but a different hash for some of them
+	ModHashChanged ModChangeKind = "hash_changed"
+
+	// ModRebuilt is a mod whose version string changed, but not its precedence, such as when only the build metadata differs
+	ModRebuilt ModChangeKind = "rebuilt"
+)
+
+type OverrideChangeKind string
+
+const (
+	OverrideAdded   OverrideChangeKind = "added"
+	OverrideRemoved OverrideChangeKind = "removed"
+	OverrideChanged OverrideChangeKind = "changed"
+)
+
+type ModChange struct {
+	ModReference string        `json:"mod_reference"`
+	Kind         ModChangeKind `json:"kind"`
+	OldVersion   string        `json:"old_version,omitempty"`
+	NewVersion   string        `json:"new_version,omitempty"`
+
+	// AddedTargets and RemovedTargets are the targets only present in the new or the old lockfile
+	AddedTargets   []string `json:"added_targets,omitempty"`
+	RemovedTargets []string `json:"removed_targets,omitempty"`
+
+	// ChangedTargets are the targets present in both lockfiles, with a different hash
+	ChangedTargets []string `json:"changed_targets,omitempty"`
+}
+
+// OverrideChange is a dependency override that differs between two lockfiles
+type OverrideChange struct {
+	ModID string             `json:"mod_id"`
+	Kind  OverrideChangeKind `json:"kind"`
+
+	// Old is nil for added overrides, and New is nil for removed ones
+	Old *LockedOverride `json:"old,omitempty"`
+	New *LockedOverride `json:"new,omitempty"`
+}
+
+// LockfileDiff lists the changed mods and dependency overrides between two lockfiles, sorted by mod id
+type LockfileDiff struct {
+	Changes   []ModChange      `json:"changes"`
+	Overrides []OverrideChange `json:"overrides,omitempty"`
+}
+
+// DiffLockfiles compares two lockfiles, either of which may be nil.
+// Changes to download links alone are not reported, as they do not change what is installed
+func DiffLockfiles(oldLockFile *LockFile, newLockFile *LockFile) *LockfileDiff {
+	oldMods := lockedMods(oldLockFile)
+	newMods := lockedMods(newLockFile)
+
+	diff := &LockfileDiff{
+		Changes: make([]ModChange, 0),
+	}
+
+	for modReference, oldMod := range oldMods {
+		if _, ok := newMods[modReference]; !ok {
+			diff.Changes = append(diff.Changes, ModChange{
+				ModReference: modReference,
+				Kind:         ModRemoved,
+				OldVersion:   oldMod.Version,
+			})
+		}
+	}
+
+	for modReference, newMod := range newMods {
+		oldMod, ok := oldMods[modReference]
+		if !ok {
+			diff.Changes = append(diff.Changes, ModChange{
+				ModReference: modReference,
+				Kind:         ModAdded,
+				NewVersion:   newMod.Version,
+			})
+			continue
+		}
+
+		change := ModChange{
+			ModReference: modReference,
+			OldVersion:   oldMod.Version,
+			NewVersion:   newMod.Version,
+		}
+
+		for target, newTarget := range newMod.Targets {
+			oldTarget, ok := oldMod.Targets[target]
+			switch {
+			case !ok:
+				change.AddedTargets = append(change.AddedTargets, target)
+			case oldTarget.Hash != newTarget.Hash:
+				change.ChangedTargets = append(change.ChangedTargets, target)
+			}
+		}
+		for target := range oldMod.Targets {
+			if _, ok := newMod.Targets[target]; !ok {
+				change.RemovedTargets = append(change.RemovedTargets, target)
+			}
+		}
+		slices.Sort(change.AddedTargets)
+		slices.Sort(change.RemovedTargets)
+		slices.Sort(change.ChangedTargets)
+
+		switch {
+		case oldMod.Version != newMod.Version:
+			switch compare := compareLockedVersions(oldMod.Version, newMod.Version); {
+			case compare > 0:
+				change.Kind = ModDowngraded
+			case compare < 0:
+				change.Kind = ModUpgraded
+			default:
+				change.Kind = ModRebuilt
+			}
+		case len(change.AddedTargets) > 0 || len(change.RemovedTargets) > 0:
+			change.Kind = ModRetargeted
+		case len(change.ChangedTargets) > 0:
+			change.Kind = ModHashChanged
+		default:
+			continue
+		}
+
+		diff.Changes = append(diff.Changes, change)
+	}
+
+	slices.SortFunc(diff.Changes, func(a, b ModChange) int {
+		return strings.Compare(a.ModReference, b.ModReference)
+	})
+
+	diff.Overrides = diffOverrides(lockedOverrides(oldLockFile), lockedOverrides(newLockFile))
+
+	return diff
+}
+
+func diffOverrides(oldOverrides map[string]LockedOverride, newOverrides map[string]LockedOverride) []OverrideChange {
+	var changes []OverrideChange
+	for modID, oldOverride := range oldOverrides {
+		oldOverride := oldOverride
+		if _, ok := newOverrides[modID]; !ok {
+			changes = append(changes, OverrideChange{
+				ModID: modID,
+				Kind:  OverrideRemoved,
+				Old:   &oldOverride,
+			})
+		}
+	}
+	for modID, newOverride := range newOverrides {
+		newOverride := newOverride
+		oldOverride, ok := oldOverrides[modID]
+		switch {
+		case !ok:
+			changes = append(changes, OverrideChange{
+				ModID: modID,
+				Kind:  OverrideAdded,
+				New:   &newOverride,
+			})
+		case oldOverride != newOverride:
+			changes = append(changes, OverrideChange{
+				ModID: modID,
+				Kind:  OverrideChanged,
+				Old:   &oldOverride,
+				New:   &newOverride,
+			})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b OverrideChange) int {
+		return strings.Compare(a.ModID, b.ModID)
+	})
+	return changes
+}
+
+// Empty reports whether the lockfiles install the same mods with the same dependency overrides
+func (d *LockfileDiff) Empty() bool {
+	return len(d.Changes) == 0 && len(d.Overrides) == 0
+}
+
+// Filter returns the changes of the given kinds
+func (d *LockfileDiff) Filter(kinds ...ModChangeKind) []ModChange {
+	var result []ModChange
+	for _, change := range d.Changes {
+		if slices.Contains(kinds, change.Kind) {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+func (d *LockfileDiff) String() string {
+	if d.Empty() {
+		return "No changes\n"
+	}
+
+	var b strings.Builder
+	for _, change := range d.Changes {
+		b.WriteString(change.String())
+		b.WriteString("\n")
+	}
+	for _, change := range d.Overrides {
+		b.WriteString(change.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (c ModChange) String() string {
+	label := strings.ReplaceAll(string(c.Kind), "_", " ")
+
+	switch c.Kind {
+	case ModAdded:
+		return fmt.Sprintf("%-12s %s %s", label, c.ModReference, c.NewVersion)
+	case ModRemoved:
+		return fmt.Sprintf("%-12s %s %s", label, c.ModReference, c.OldVersion)
+	case ModRetargeted:
+		var details []string
+		if len(c.AddedTargets) > 0 {
+			details = append(details, "+"+strings.Join(c.AddedTargets, " +"))
+		}
+		if len(c.RemovedTargets) > 0 {
+			details = append(details, "-"+strings.Join(c.RemovedTargets, " -"))
+		}
+		return fmt.Sprintf("%-12s %s %s (%s)", label, c.ModReference, c.NewVersion, strings.Join(details, " "))
+	case ModHashChanged:
+		return fmt.Sprintf("%-12s %s %s (%s)", label, c.ModReference, c.NewVersion, strings.Join(c.ChangedTargets, ", "))
+	default:
+		return fmt.Sprintf("%-12s %s %s -> %s", label, c.ModReference, c.OldVersion, c.NewVersion)
+	}
+}
+
+func (c OverrideChange) String() string {
+	switch c.Kind {
+	case OverrideAdded:
+		return fmt.Sprintf("%-12s override %s %s", c.Kind, c.ModID, c.New)
+	case OverrideRemoved:
+		return fmt.Sprintf("%-12s override %s %s", c.Kind, c.ModID, c.Old)
+	default:
+		return fmt.Sprintf("%-12s override %s %s -> %s", c.Kind, c.ModID, c.Old, c.New)
+	}
+}
+
+func lockedOverrides(lockFile *LockFile) map[string]LockedOverride {
+	if lockFile == nil {
+		return nil
+	}
+	return lockFile.Overrides
+}
+
+func lockedMods(lockFile *LockFile) map[string]LockedMod {
+	if lockFile == nil {
+		return nil
+	}
+	return lockFile.Mods
+}
+
+// compareLockedVersions compares two versions, falling back to comparing them as strings if they are not semver
+func compareLockedVersions(a string, b string) int {
+	aVersion, aErr := semver.NewVersion(a)
+	bVersion, bErr := semver.NewVersion(b)
+	if aErr != nil || bErr != nil {
+		return strings.Compare(a, b)
+	}
+	return aVersion.Compare(bVersion)
+}
@@ -0,0 +1,89 @@
+package resolver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+func TestDiffLockfiles(t *testing.T) {
+	windows := LockedModTarget{Hash: "windows", Link: "https://example.com/windows"}
+	linux := LockedModTarget{Hash: "linux", Link: "https://example.com/linux"}
+
+	old := NewLockfile()
+	old.Mods["SML"] = LockedMod{Version: "3.6.0", Targets: map[string]LockedModTarget{"Windows": windows}}
+	old.Mods["RefinedPower"] = LockedMod{Version: "3.2.13", Targets: map[string]LockedModTarget{"Windows": windows}}
+	old.Mods["ModularUI"] = LockedMod{Version: "2.1.11", Targets: map[string]LockedModTarget{"Windows": windows}}
+	old.Mods["RefinedRDLib"] = LockedMod{Version: "1.1.7", Targets: map[string]LockedModTarget{"Windows": windows}}
+	old.Mods["ComplexMod"] = LockedMod{Version: "3.0.0", Targets: map[string]LockedModTarget{"Windows": windows, "LinuxServer": linux}}
+	old.Mods["Unchanged"] = LockedMod{Version: "1.0.0", Targets: map[string]LockedModTarget{"Windows": windows}}
+	old.Mods["NightlyMod"] = LockedMod{Version: "2.0.0+build.1", Targets: map[string]LockedModTarget{"Windows": windows}}
+	old.Overrides = map[string]LockedOverride{
+		"SML":       {Condition: "^3.6.0"},
+		"ModularUI": {Condition: "^2.1.0"},
+	}
+
+	updated := old.Clone()
+	updated.Mods["SML"] = LockedMod{Version: "3.6.1", Targets: map[string]LockedModTarget{"Windows": windows}}
+	updated.Mods["RefinedPower"] = LockedMod{Version: "3.2.10", Targets: map[string]LockedModTarget{"Windows": windows}}
+	delete(updated.Mods, "ModularUI")
+	updated.Mods["MapOverhaul"] = LockedMod{Version: "1.0.0", Targets: map[string]LockedModTarget{"Windows": windows}}
+	updated.Mods["RefinedRDLib"] = LockedMod{Version: "1.1.7", Targets: map[string]LockedModTarget{"Windows": {Hash: "rebuilt", Link: windows.Link}}}
+	updated.Mods["ComplexMod"] = LockedMod{Version: "3.0.0", Targets: map[string]LockedModTarget{"Windows": windows, "WindowsServer": windows}}
+	updated.Mods["Unchanged"] = LockedMod{Version: "1.0.0", Targets: map[string]LockedModTarget{"Windows": {Hash: windows.Hash, Link: "https://mirror.example.com/windows"}}}
+	updated.Mods["NightlyMod"] = LockedMod{Version: "2.0.0+build.2", Targets: map[string]LockedModTarget{"Windows": windows}}
+	updated.Overrides = map[string]LockedOverride{
+		"SML":          {Condition: "^3.6.1", ReplaceWith: "SMLFork"},
+		"RefinedRDLib": {Condition: "^1.1.0"},
+	}
+
+	diff := DiffLockfiles(old, updated)
+	testza.AssertEqual(t, []ModChange{
+		{ModReference: "ComplexMod", Kind: ModRetargeted, OldVersion: "3.0.0", NewVersion: "3.0.0", AddedTargets: []string{"WindowsServer"}, RemovedTargets: []string{"LinuxServer"}},
+		{ModReference: "MapOverhaul", Kind: ModAdded, NewVersion: "1.0.0"},
+		{ModReference: "ModularUI", Kind: ModRemoved, OldVersion: "2.1.11"},
+		{ModReference: "NightlyMod", Kind: ModRebuilt, OldVersion: "2.0.0+build.1", NewVersion: "2.0.0+build.2"},
+		{ModReference: "RefinedPower", Kind: ModDowngraded, OldVersion: "3.2.13", NewVersion: "3.2.10"},
+		{ModReference: "RefinedRDLib", Kind: ModHashChanged, OldVersion: "1.1.7", NewVersion: "1.1.7", ChangedTargets: []string{"Windows"}},
+		{ModReference: "SML", Kind: ModUpgraded, OldVersion: "3.6.0", NewVersion: "3.6.1"},
+	}, diff.Changes)
+	testza.AssertEqual(t, []OverrideChange{
+		{ModID: "ModularUI", Kind: OverrideRemoved, Old: &LockedOverride{Condition: "^2.1.0"}},
+		{ModID: "RefinedRDLib", Kind: OverrideAdded, New: &LockedOverride{Condition: "^1.1.0"}},
+		{ModID: "SML", Kind: OverrideChanged, Old: &LockedOverride{Condition: "^3.6.0"}, New: &LockedOverride{Condition: "^3.6.1", ReplaceWith: "SMLFork"}},
+	}, diff.Overrides)
+
+	testza.AssertEqual(t, `retargeted   ComplexMod 3.0.0 (+WindowsServer -LinuxServer)
+added        MapOverhaul 1.0.0
+removed      ModularUI 2.1.11
+rebuilt      NightlyMod 2.0.0+build.1 -> 2.0.0+build.2
+downgraded   RefinedPower 3.2.13 -> 3.2.10
+hash changed RefinedRDLib 1.1.7 (Windows)
+upgraded     SML 3.6.0 -> 3.6.1
+removed      override ModularUI ^2.1.0
+added        override RefinedRDLib ^1.1.0
+changed      override SML ^3.6.0 -> replaced with SMLFork ^3.6.1
+`, diff.String())
+
+	data, err := json.Marshal(diff.Filter(ModAdded, ModUpgraded))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, `[{"mod_reference":"MapOverhaul","kind":"added","new_version":"1.0.0"},{"mod_reference":"SML","kind":"upgraded","old_version":"3.6.0","new_version":"3.6.1"}]`, string(data))
+
+	data, err = json.Marshal(DiffLockfiles(&LockFile{Overrides: old.Overrides}, &LockFile{Overrides: updated.Overrides}))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, `{"changes":[],"overrides":[{"mod_id":"ModularUI","kind":"removed","old":{"condition":"^2.1.0"}},{"mod_id":"RefinedRDLib","kind":"added","new":{"condition":"^1.1.0"}},{"mod_id":"SML","kind":"changed","old":{"condition":"^3.6.0"},"new":{"condition":"^3.6.1","replace_with":"SMLFork"}}]}`, string(data))
+}
+
+func TestDiffLockfilesEmpty(t *testing.T) {
+	diff := DiffLockfiles(nil, NewLockfile())
+	testza.AssertTrue(t, diff.Empty())
+	testza.AssertEqual(t, "No changes\n", diff.String())
+
+	data, err := json.Marshal(diff)
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, `{"changes":[]}`, string(data))
+
+	diff = DiffLockfiles(nil, &LockFile{Mods: map[string]LockedMod{"SML": {Version: "3.6.1"}}})
+	testza.AssertEqual(t, "added        SML 3.6.1\n", diff.String())
+}
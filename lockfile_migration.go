@@ -0,0 +1,130 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// lockfileMigration converts the JSON of a lockfile from one version to the next one
+type lockfileMigration func(data []byte) ([]byte, error)
+
+// lockfileMigrations upgrade a lockfile from the version they are indexed by to the next version.
+// Adding a LockfileVersion requires adding the migration from the previous version here
+var lockfileMigrations = [CurrentLockfileVersion]lockfileMigration{
+	InitialLockfileVersion:    migrateInitialLockfile,
+	ModTargetsLockfileVersion: migrateModTargetsLockfile,
+}
+
+// lockfileVersion detects the version of a lockfile.
+// Initial lockfiles have no version, they are a map of the locked mods
+func lockfileVersion(data []byte) (LockfileVersion, error) {
+	var header map[string]json.RawMessage
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, fmt.Errorf("failed to decode lockfile: %w", err)
+	}
+
+	rawVersion, ok := header["version"]
+	if !ok {
+		return InitialLockfileVersion, nil
+	}
+
+	var version LockfileVersion
+	if err := json.Unmarshal(rawVersion, &version); err != nil {
+		// A mod named "version" in an initial lockfile
+		if _, hasMods := header["mods"]; !hasMods {
+			return InitialLockfileVersion, nil
+		}
+		return 0, fmt.Errorf("failed to decode lockfile version: %w", err)
+	}
+
+	return version, nil
+}
+
+func migrateLockfile(data []byte, version LockfileVersion) ([]byte, error) {
+	for ; version < CurrentLockfileVersion; version++ {
+		migrated, err := lockfileMigrations[version](data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate lockfile from version %d: %w", version, err)
+		}
+		data = migrated
+	}
+	return data, nil
+}
+
+type initialLockedMod struct {
+	Version      string            `json:"version"`
+	Hash         string            `json:"hash"`
+	Link         string            `json:"link"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// migrateInitialLockfile moves the mods under the mods key,
+// and their single download into a Windows target, the only target that existed before targets were locked
+func migrateInitialLockfile(data []byte) ([]byte, error) {
+	var mods map[string]initialLockedMod
+	if err := json.Unmarshal(data, &mods); err != nil {
+		return nil, fmt.Errorf("failed to decode mods: %w", err)
+	}
+
+	lockFile := struct {
+		Mods    map[string]LockedMod `json:"mods"`
+		Version LockfileVersion      `json:"version"`
+	}{
+		Mods:    make(map[string]LockedMod, len(mods)),
+		Version: ModTargetsLockfileVersion,
+	}
+
+	for modReference, mod := range mods {
+		targets := make(map[string]LockedModTarget)
+		if mod.Hash != "" || mod.Link != "" {
+			targets["Windows"] = LockedModTarget{
+				Hash: mod.Hash,
+				Link: mod.Link,
+			}
+		}
+		lockFile.Mods[modReference] = LockedMod{
+			Version:      mod.Version,
+			Dependencies: mod.Dependencies,
+			Targets:      targets,
+		}
+	}
+
+	migrated, err := json.Marshal(lockFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode mods: %w", err)
+	}
+	return migrated, nil
+}
+
+// migrateModTargetsLockfile marks every mod as required on remote, since that was not recorded,
+// and being required is the safe assumption when checking client compatibility.
+// Target sizes and game versions stay unknown
+func migrateModTargetsLockfile(data []byte) ([]byte, error) {
+	var lockFile map[string]json.RawMessage
+	if err := json.Unmarshal(data, &lockFile); err != nil {
+		return nil, fmt.Errorf("failed to decode lockfile: %w", err)
+	}
+
+	var mods map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(lockFile["mods"], &mods); err != nil {
+		return nil, fmt.Errorf("failed to decode mods: %w", err)
+	}
+
+	for _, mod := range mods {
+		mod["required_on_remote"] = json.RawMessage("true")
+	}
+
+	var err error
+	if lockFile["mods"], err = json.Marshal(mods); err != nil {
+		return nil, fmt.Errorf("failed to encode mods: %w", err)
+	}
+	if lockFile["version"], err = json.Marshal(ModMetadataLockfileVersion); err != nil {
+		return nil, fmt.Errorf("failed to encode version: %w", err)
+	}
+
+	migrated, err := json.Marshal(lockFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode lockfile: %w", err)
+	}
+	return migrated, nil
+}
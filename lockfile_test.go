@@ -1,6 +1,9 @@
 package resolver
 
 import (
+	"bytes"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/MarvinJWendt/testza"
@@ -48,3 +51,168 @@ func TestLockFile(t *testing.T) {
 
 	testza.AssertEqual(t, secondLockFile.Mods["Foo"].Version, "")
 }
+
+func TestLockFileDeepClone(t *testing.T) {
+	original := NewLockfile()
+	original.Mods["RefinedPower"] = LockedMod{
+		Version:      "3.2.13",
+		Dependencies: map[string]string{"SML": "^3.6.1"},
+		Targets:      map[string]LockedModTarget{"Windows": {Hash: "abc"}},
+	}
+	original.Overrides = map[string]LockedOverride{"SML": {Condition: "^3.6.0"}}
+
+	clone := original.Clone()
+	clone.Mods["RefinedPower"].Dependencies["ModularUI"] = "^2.1.11"
+	clone.Mods["RefinedPower"].Targets["Windows"] = LockedModTarget{Hash: "changed"}
+	clone.Overrides["SML"] = LockedOverride{ReplaceWith: "SMLFork"}
+
+	testza.AssertEqual(t, map[string]string{"SML": "^3.6.1"}, original.Mods["RefinedPower"].Dependencies)
+	testza.AssertEqual(t, "abc", original.Mods["RefinedPower"].Targets["Windows"].Hash)
+	testza.AssertEqual(t, "^3.6.0", original.Overrides["SML"].Condition)
+}
+
+func TestLockFileHelpers(t *testing.T) {
+	original := NewLockfile()
+	original.Mods["SML"] = LockedMod{Version: "3.6.1", Targets: map[string]LockedModTarget{"Windows": {Hash: "sml"}}}
+	original.Mods["RefinedPower"] = LockedMod{Version: "3.2.13", Targets: map[string]LockedModTarget{"Windows": {Hash: "rp"}}}
+
+	with := original.With("ModularUI", LockedMod{Version: "2.1.11"})
+	testza.AssertLen(t, with.Mods, 3)
+	testza.AssertLen(t, original.Mods, 2)
+
+	without := original.Without("SML")
+	testza.AssertLen(t, without.Mods, 1)
+	testza.AssertEqual(t, "3.2.13", without.Mods["RefinedPower"].Version)
+	testza.AssertLen(t, original.Mods, 2)
+
+	filtered := original.Filter(func(modReference string, _ LockedMod) bool {
+		return modReference == "SML"
+	})
+	testza.AssertLen(t, filtered.Mods, 1)
+	testza.AssertEqual(t, "3.6.1", filtered.Mods["SML"].Version)
+	testza.AssertLen(t, original.Mods, 2)
+
+	other := NewLockfile()
+	other.Mods["SML"] = LockedMod{Version: "3.7.0"}
+	other.Overrides = map[string]LockedOverride{"SML": {Condition: "^3.7.0"}}
+
+	merged := original.Merge(other)
+	testza.AssertLen(t, merged.Mods, 2)
+	testza.AssertEqual(t, "3.7.0", merged.Mods["SML"].Version)
+	testza.AssertEqual(t, "^3.7.0", merged.Overrides["SML"].Condition)
+	testza.AssertEqual(t, "3.6.1", original.Mods["SML"].Version)
+	testza.AssertNil(t, original.Overrides)
+
+	merged.Mods["RefinedPower"].Targets["Windows"] = LockedModTarget{Hash: "changed"}
+	testza.AssertEqual(t, "rp", original.Mods["RefinedPower"].Targets["Windows"].Hash)
+}
+
+const currentLockfileJSON = `{
+  "mods": {
+    "RefinedPower": {
+      "dependencies": {
+        "ModularUI": "^2.1.11",
+        "RefinedRDLib": "^1.1.7",
+        "SML": "^3.6.1"
+      },
+      "targets": {
+        "Windows": {
+          "hash": "abc",
+          "link": "https://api.ficsit.app/v1/version/7QcfLdsCx/Windows/download?a=1&b=2",
+          "size": 1048576
+        }
+      },
+      "version": "3.2.13",
+      "required_on_remote": true,
+      "game_version": ">=264901"
+    },
+    "SML": {
+      "dependencies": {},
+      "targets": {},
+      "version": "3.6.1",
+      "required_on_remote": true
+    }
+  },
+  "version": 2
+}
+`
+
+func TestLockfileReadWrite(t *testing.T) {
+	lockFile, err := ReadLockfile(strings.NewReader(currentLockfileJSON))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, CurrentLockfileVersion, lockFile.Version)
+	testza.AssertEqual(t, "3.2.13", lockFile.Mods["RefinedPower"].Version)
+	testza.AssertEqual(t, int64(1048576), lockFile.Mods["RefinedPower"].Targets["Windows"].Size)
+
+	var buf bytes.Buffer
+	n, err := lockFile.WriteTo(&buf)
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, int64(buf.Len()), n)
+	testza.AssertEqual(t, currentLockfileJSON, buf.String())
+
+	var empty bytes.Buffer
+	_, err = (&LockFile{Version: CurrentLockfileVersion}).WriteTo(&empty)
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "{\n  \"mods\": {},\n  \"version\": 2\n}\n", empty.String())
+}
+
+func TestLockfileMigration(t *testing.T) {
+	lockFile, err := ReadLockfile(strings.NewReader(`{
+		"SML": {"version": "3.6.1", "hash": "abc", "link": "https://example.com/SML.zip", "dependencies": {}},
+		"RefinedPower": {"version": "3.2.13", "dependencies": {"SML": "^3.6.1"}}
+	}`))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, &LockFile{
+		Version: CurrentLockfileVersion,
+		Mods: map[string]LockedMod{
+			"SML": {
+				Version:      "3.6.1",
+				Dependencies: map[string]string{},
+				Targets: map[string]LockedModTarget{
+					"Windows": {Hash: "abc", Link: "https://example.com/SML.zip"},
+				},
+				RequiredOnRemote: true,
+			},
+			"RefinedPower": {
+				Version:          "3.2.13",
+				Dependencies:     map[string]string{"SML": "^3.6.1"},
+				Targets:          map[string]LockedModTarget{},
+				RequiredOnRemote: true,
+			},
+		},
+	}, lockFile)
+}
+
+func TestLockfileMigrationFromModTargets(t *testing.T) {
+	lockFile, err := ReadLockfile(strings.NewReader(`{
+		"mods": {
+			"SML": {"version": "3.6.1", "dependencies": {}, "targets": {"Windows": {"hash": "abc", "link": "https://example.com/SML.zip"}}}
+		},
+		"overrides": {"SML": {"condition": "^3.6.0"}},
+		"version": 1
+	}`))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, &LockFile{
+		Version: CurrentLockfileVersion,
+		Mods: map[string]LockedMod{
+			"SML": {
+				Version:      "3.6.1",
+				Dependencies: map[string]string{},
+				Targets: map[string]LockedModTarget{
+					"Windows": {Hash: "abc", Link: "https://example.com/SML.zip"},
+				},
+				RequiredOnRemote: true,
+			},
+		},
+		Overrides: map[string]LockedOverride{"SML": {Condition: "^3.6.0"}},
+	}, lockFile)
+}
+
+func TestLockfileUnsupportedVersion(t *testing.T) {
+	_, err := ReadLockfile(strings.NewReader(`{"mods": {}, "version": 99}`))
+	testza.AssertEqual(t, "unsupported lockfile version 99, the latest supported version is 2", err.Error())
+	testza.AssertTrue(t, errors.Is(err, ErrUnsupportedLockfileVersion))
+
+	_, err = ReadLockfile(strings.NewReader(`not json`))
+	testza.AssertNotNil(t, err)
+}
@@ -0,0 +1,114 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+// DependencyOverride changes the dependency every mod declares on ModID
+type DependencyOverride struct {
+	ModID string
+
+	// Condition replaces the declared condition, if not empty
+	Condition string
+
+	// ReplaceWith makes mods depend on this mod instead of ModID (e.g. a fork), if not empty.
+	// The declared condition, or Condition if set, is then applied to the replacement
+	ReplaceWith string
+}
+
+type overrides map[string]DependencyOverride
+
+func newOverrides(list []DependencyOverride) (overrides, error) {
+	result := make(overrides, len(list))
+	for _, override := range list {
+		if override.ModID == "" {
+			return nil, errors.New("dependency override is missing the mod id")
+		}
+		if override.Condition == "" && override.ReplaceWith == "" {
+			return nil, fmt.Errorf("dependency override for %s must set a condition or a replacement", override.ModID)
+		}
+		if _, ok := result[override.ModID]; ok {
+			return nil, fmt.Errorf("duplicate dependency override for %s", override.ModID)
+		}
+		if override.Condition != "" {
+			if _, err := semver.NewConstraint(override.Condition); err != nil {
+				return nil, fmt.Errorf("failed to parse override constraint %s: %w", override.Condition, err)
+			}
+		}
+		result[override.ModID] = override
+	}
+	return result, nil
+}
+
+// apply returns a copy of the mod versions with the overrides applied to their dependencies.
+// A replacement can make a version depend on the same mod twice, those dependencies are merged into one
+// with the intersection of both conditions, which is required unless both were optional
+func (o overrides) apply(modVersions []ModVersion) []ModVersion {
+	if len(o) == 0 {
+		return modVersions
+	}
+
+	result := make([]ModVersion, 0, len(modVersions))
+	for _, modVersion := range modVersions {
+		dependencies := make([]Dependency, 0, len(modVersion.Dependencies))
+		indices := make(map[string]int, len(modVersion.Dependencies))
+		for _, dependency := range modVersion.Dependencies {
+			if override, ok := o[dependency.ModID]; ok {
+				if override.ReplaceWith != "" {
+					dependency.ModID = override.ReplaceWith
+				}
+				if override.Condition != "" {
+					dependency.Condition = override.Condition
+				}
+			}
+
+			if i, ok := indices[dependency.ModID]; ok {
+				if merged, ok := mergeDependencies(dependencies[i], dependency); ok {
+					dependencies[i] = merged
+					continue
+				}
+			}
+
+			indices[dependency.ModID] = len(dependencies)
+			dependencies = append(dependencies, dependency)
+		}
+		modVersion.Dependencies = dependencies
+		result = append(result, modVersion)
+	}
+	return result
+}
+
+// mergeDependencies combines two dependencies on the same mod into one satisfying both.
+// Invalid conditions are not merged, so that they are reported when the dependencies are parsed
+func mergeDependencies(a Dependency, b Dependency) (Dependency, bool) {
+	aConstraint, err := semver.NewConstraint(a.Condition)
+	if err != nil {
+		return a, false
+	}
+	bConstraint, err := semver.NewConstraint(b.Condition)
+	if err != nil {
+		return a, false
+	}
+	return Dependency{
+		ModID:     a.ModID,
+		Condition: aConstraint.Intersect(bConstraint).String(),
+		Optional:  a.Optional && b.Optional,
+	}, true
+}
+
+func (o overrides) locked() map[string]LockedOverride {
+	if len(o) == 0 {
+		return nil
+	}
+	result := make(map[string]LockedOverride, len(o))
+	for modID, override := range o {
+		result[modID] = LockedOverride{
+			Condition:   override.Condition,
+			ReplaceWith: override.ReplaceWith,
+		}
+	}
+	return result
+}
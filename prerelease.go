@@ -0,0 +1,44 @@
+package resolver
+
+import (
+	"regexp"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+type PrereleasePolicy int
+
+const (
+	// PrereleaseAlways allows pre-release versions to be picked like any other version
+	PrereleaseAlways PrereleasePolicy = iota
+
+	// PrereleaseExplicit only allows pre-release versions of a mod if its requested constraint names a pre-release.
+	// Only the root constraints are considered, a dependency constraint naming a pre-release does not allow one,
+	// since the versions of a mod are filtered before all the mods depending on it are known
+	PrereleaseExplicit
+
+	// PrereleaseNever never allows pre-release versions to be picked
+	PrereleaseNever
+)
+
+var prereleaseConstraintRegex = regexp.MustCompile(`[0-9]-[0-9A-Za-z]`)
+
+func (f *ficsitAPISource) allowsPrerelease(pkg string, v semver.Version) bool {
+	policy := f.prereleasePolicy
+	if override, ok := f.prereleaseOverrides[pkg]; ok {
+		policy = override
+	}
+
+	switch policy {
+	case PrereleaseAlways:
+		return true
+	case PrereleaseExplicit:
+		constraint, ok := f.toInstall[pkg]
+		if !ok || !prereleaseConstraintRegex.MatchString(constraint.RawString()) {
+			return false
+		}
+		return constraint.Contains(v)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,104 @@
+package resolver
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+type extraVersionsProvider struct {
+	MockProvider
+	extra map[string][]ModVersion
+}
+
+func (p extraVersionsProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	versions, err := p.MockProvider.ModVersionsWithDependencies(ctx, modID)
+	if err != nil {
+		return nil, err
+	}
+	return append(versions, p.extra[modID]...), nil
+}
+
+var smlBetaProvider = extraVersionsProvider{
+	extra: map[string][]ModVersion{
+		"SML": {
+			{
+				Version:          "3.7.0-beta.1",
+				GameVersion:      ">=264901",
+				RequiredOnRemote: true,
+				Targets:          commonTargets,
+			},
+		},
+	},
+}
+
+func TestPrereleasePolicy(t *testing.T) {
+	resolver := NewDependencyResolver(smlBetaProvider)
+
+	resolve := func(constraint string, opts ...ResolveOption) (*ResolveResult, error) {
+		return resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+			"SML": constraint,
+		}, append(opts, WithGameVersion(math.MaxInt))...))
+	}
+
+	result, err := resolve(">3.6.1")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.7.0-beta.1", result.LockFile.Mods["SML"].Version)
+
+	result, err = resolve("*", WithPrereleasePolicy(PrereleaseExplicit))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.6.1", result.LockFile.Mods["SML"].Version)
+
+	_, err = resolve(">3.6.1", WithPrereleasePolicy(PrereleaseExplicit))
+	testza.AssertNotNil(t, err)
+
+	result, err = resolve(">=3.7.0-beta.1", WithPrereleasePolicy(PrereleaseExplicit))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.7.0-beta.1", result.LockFile.Mods["SML"].Version)
+
+	_, err = resolve(">=3.7.0-beta.1", WithPrereleasePolicy(PrereleaseNever))
+	testza.AssertNotNil(t, err)
+
+	result, err = resolve(">3.6.1", WithPrereleasePolicy(PrereleaseNever), WithModPrereleasePolicy("SML", PrereleaseAlways))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.7.0-beta.1", result.LockFile.Mods["SML"].Version)
+}
+
+func TestPrereleaseExplicitIgnoresDependencies(t *testing.T) {
+	provider := extraVersionsProvider{
+		extra: map[string][]ModVersion{
+			"SML": smlBetaProvider.extra["SML"],
+			"RefinedRDLib": {
+				{
+					Version:          "1.2.0",
+					RequiredOnRemote: true,
+					Dependencies: []Dependency{
+						{
+							ModID:     "SML",
+							Condition: ">=3.7.0-beta.1",
+						},
+					},
+					Targets: commonTargets,
+				},
+			},
+		},
+	}
+	resolver := NewDependencyResolver(provider)
+
+	resolve := func(constraints map[string]string, opts ...ResolveOption) (*ResolveResult, error) {
+		return resolver.Resolve(context.Background(), NewResolveRequest(constraints, append(opts, WithGameVersion(math.MaxInt))...))
+	}
+
+	_, err := resolve(map[string]string{"RefinedRDLib": "1.2.0"}, WithPrereleasePolicy(PrereleaseExplicit))
+	testza.AssertNotNil(t, err)
+
+	result, err := resolve(map[string]string{"RefinedRDLib": "1.2.0", "SML": ">=3.7.0-beta.1"}, WithPrereleasePolicy(PrereleaseExplicit))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.7.0-beta.1", result.LockFile.Mods["SML"].Version)
+
+	result, err = resolve(map[string]string{"RefinedRDLib": "1.2.0"}, WithPrereleasePolicy(PrereleaseExplicit), WithModPrereleasePolicy("SML", PrereleaseAlways))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.7.0-beta.1", result.LockFile.Mods["SML"].Version)
+}
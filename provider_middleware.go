@@ -0,0 +1,40 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// ProviderMiddleware wraps a Provider to add behaviour around its requests
+type ProviderMiddleware func(Provider) Provider
+
+// ChainProvider wraps the provider with the middlewares.
+// The first middleware is the outermost one, so it sees every request first
+func ChainProvider(provider Provider, middlewares ...ProviderMiddleware) Provider {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		provider = middlewares[i](provider)
+	}
+	return provider
+}
+
+// IsTransientError reports whether a provider error is likely to go away when the request is repeated,
+// such as ErrProviderUnavailable and network timeouts. An open circuit breaker is not transient,
+// as retrying it immediately cannot succeed
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+
+	if errors.Is(err, ErrProviderUnavailable) {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
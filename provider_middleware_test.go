@@ -0,0 +1,209 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+// flakyProvider fails the first failures requests with err, then behaves like MockProvider
+type flakyProvider struct {
+	MockProvider
+	err      error
+	failures atomic.Int32
+	calls    atomic.Int32
+}
+
+func newFlakyProvider(failures int32, err error) *flakyProvider {
+	p := &flakyProvider{err: err}
+	p.failures.Store(failures)
+	return p
+}
+
+func (p *flakyProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	p.calls.Add(1)
+	if p.failures.Add(-1) >= 0 {
+		return nil, p.err
+	}
+	return p.MockProvider.ModVersionsWithDependencies(ctx, modID)
+}
+
+var errBadGateway = &HTTPStatusError{StatusCode: http.StatusBadGateway, Operation: "ModVersionsWithDependencies"}
+
+func TestIsTransientError(t *testing.T) {
+	testza.AssertTrue(t, IsTransientError(errBadGateway))
+	testza.AssertTrue(t, IsTransientError(&HTTPStatusError{StatusCode: http.StatusTooManyRequests}))
+	testza.AssertTrue(t, IsTransientError(&net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+	testza.AssertFalse(t, IsTransientError(&HTTPStatusError{StatusCode: http.StatusBadRequest}))
+	testza.AssertTrue(t, IsTransientError(fmt.Errorf("wrapped: %w", ErrProviderUnavailable)))
+	testza.AssertFalse(t, IsTransientError(ErrModNotFound))
+	testza.AssertFalse(t, IsTransientError(context.Canceled))
+	testza.AssertFalse(t, IsTransientError(ErrCircuitOpen))
+	testza.AssertFalse(t, IsTransientError(nil))
+}
+
+func TestGraphQLProviderTransientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := NewGraphQLProvider(server.URL, nil).ModVersionsWithDependencies(context.Background(), "SML")
+	testza.AssertEqual(t, "unexpected status code 503 for ModVersionsWithDependencies request", err.Error())
+	testza.AssertTrue(t, IsTransientError(err))
+	testza.AssertTrue(t, errors.Is(err, ErrProviderUnavailable))
+}
+
+func TestRetryProvider(t *testing.T) {
+	clock := newFakeClock()
+	flaky := newFlakyProvider(2, errBadGateway)
+	provider := NewRetryingProvider(flaky, RetryOptions{Clock: clock})
+
+	versions, err := provider.ModVersionsWithDependencies(context.Background(), "SML")
+	testza.AssertNoError(t, err)
+	testza.AssertNotNil(t, versions)
+	testza.AssertEqual(t, int32(3), flaky.calls.Load())
+	testza.AssertEqual(t, []time.Duration{500 * time.Millisecond, time.Second}, clock.Sleeps())
+}
+
+func TestRetryProviderGivesUp(t *testing.T) {
+	clock := newFakeClock()
+	flaky := newFlakyProvider(100, errBadGateway)
+	provider := NewRetryingProvider(flaky, RetryOptions{
+		MaxAttempts: 5,
+		BaseDelay:   time.Second,
+		MaxDelay:    3 * time.Second,
+		Clock:       clock,
+	})
+
+	_, err := provider.ModVersionsWithDependencies(context.Background(), "SML")
+	testza.AssertEqual(t, "giving up after 5 attempts: unexpected status code 502 for ModVersionsWithDependencies request", err.Error())
+	testza.AssertTrue(t, errors.Is(err, errBadGateway))
+	testza.AssertEqual(t, int32(5), flaky.calls.Load())
+	testza.AssertEqual(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}, clock.Sleeps())
+}
+
+func TestRetryProviderPermanentError(t *testing.T) {
+	clock := newFakeClock()
+	flaky := newFlakyProvider(1, ErrModNotFound)
+	provider := NewRetryingProvider(flaky, RetryOptions{Clock: clock})
+
+	_, err := provider.ModVersionsWithDependencies(context.Background(), "SML")
+	testza.AssertEqual(t, "mod not found", err.Error())
+	testza.AssertEqual(t, int32(1), flaky.calls.Load())
+	testza.AssertLen(t, clock.Sleeps(), 0)
+}
+
+func TestRetryProviderCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	flaky := newFlakyProvider(1, errBadGateway)
+	provider := NewRetryingProvider(flaky, RetryOptions{Clock: newFakeClock()})
+
+	_, err := provider.ModVersionsWithDependencies(ctx, "SML")
+	testza.AssertTrue(t, errors.Is(err, context.Canceled))
+	testza.AssertTrue(t, errors.Is(err, errBadGateway))
+	testza.AssertEqual(t, int32(1), flaky.calls.Load())
+}
+
+func TestRateLimitProvider(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+	provider := NewRateLimitedProvider(MockProvider{}, RateLimitOptions{
+		Rate:  2,
+		Burst: 2,
+		Clock: clock,
+	})
+
+	for i := 0; i < 5; i++ {
+		_, err := provider.ModVersionsWithDependencies(ctx, "SML")
+		testza.AssertNoError(t, err)
+	}
+	// The burst is used up by the first two requests, the rest wait for a token each
+	testza.AssertEqual(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond}, clock.Sleeps())
+
+	// The bucket refills while idle, but never above the burst
+	clock.Advance(time.Minute)
+	for i := 0; i < 3; i++ {
+		_, err := provider.GetModName(ctx, "SML")
+		testza.AssertNoError(t, err)
+	}
+	testza.AssertLen(t, clock.Sleeps(), 4)
+}
+
+func TestCircuitBreakerProvider(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+	flaky := newFlakyProvider(3, errBadGateway)
+	provider := NewCircuitBreakerProvider(flaky, CircuitBreakerOptions{
+		FailureThreshold: 2,
+		Cooldown:         10 * time.Second,
+		Clock:            clock,
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := provider.ModVersionsWithDependencies(ctx, "SML")
+		testza.AssertTrue(t, errors.Is(err, errBadGateway))
+	}
+
+	_, err := provider.ModVersionsWithDependencies(ctx, "SML")
+	testza.AssertEqual(t, "circuit breaker is open after 2 consecutive failures: provider unavailable", err.Error())
+	testza.AssertTrue(t, errors.Is(err, ErrCircuitOpen))
+	testza.AssertTrue(t, errors.Is(err, ErrProviderUnavailable))
+	testza.AssertEqual(t, int32(2), flaky.calls.Load())
+
+	// The trial request after the cooldown fails, so the circuit opens again
+	clock.Advance(10 * time.Second)
+	_, err = provider.ModVersionsWithDependencies(ctx, "SML")
+	testza.AssertTrue(t, errors.Is(err, errBadGateway))
+	_, err = provider.ModVersionsWithDependencies(ctx, "SML")
+	testza.AssertTrue(t, errors.Is(err, ErrCircuitOpen))
+	testza.AssertEqual(t, int32(3), flaky.calls.Load())
+
+	// The next trial succeeds and closes the circuit
+	clock.Advance(10 * time.Second)
+	for i := 0; i < 3; i++ {
+		_, err = provider.ModVersionsWithDependencies(ctx, "SML")
+		testza.AssertNoError(t, err)
+	}
+	testza.AssertEqual(t, int32(6), flaky.calls.Load())
+}
+
+func TestCircuitBreakerIgnoresPermanentErrors(t *testing.T) {
+	flaky := newFlakyProvider(5, ErrModNotFound)
+	provider := NewCircuitBreakerProvider(flaky, CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Clock:            newFakeClock(),
+	})
+
+	for i := 0; i < 5; i++ {
+		_, err := provider.ModVersionsWithDependencies(context.Background(), "SML")
+		testza.AssertEqual(t, "mod not found", err.Error())
+	}
+	testza.AssertEqual(t, int32(5), flaky.calls.Load())
+}
+
+func TestChainProvider(t *testing.T) {
+	clock := newFakeClock()
+	flaky := newFlakyProvider(100, errBadGateway)
+	provider := ChainProvider(flaky,
+		Retry(RetryOptions{MaxAttempts: 10, Clock: clock}),
+		CircuitBreaker(CircuitBreakerOptions{FailureThreshold: 3, Clock: clock}),
+		RateLimit(RateLimitOptions{Rate: 1, Burst: 10, Clock: clock}),
+	)
+
+	// Retries stop as soon as the circuit opens, since an open circuit is not a transient error
+	_, err := provider.ModVersionsWithDependencies(context.Background(), "SML")
+	testza.AssertTrue(t, errors.Is(err, ErrCircuitOpen))
+	testza.AssertEqual(t, int32(3), flaky.calls.Load())
+	testza.AssertLen(t, clock.Sleeps(), 3)
+}
@@ -0,0 +1,108 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type RateLimitOptions struct {
+	// Rate is the sustained number of requests allowed per second, zero disables the limit
+	Rate float64
+
+	// Burst is the number of requests that can be made at once after being idle, defaults to 1
+	Burst int
+
+	Clock Clock
+}
+
+var _ SearchableProvider = (*RateLimitedProvider)(nil)
+
+// RateLimitedProvider limits the requests made to another Provider using a token bucket.
+// Requests over the limit wait for their turn, rather than fail
+type RateLimitedProvider struct {
+	provider Provider
+	options  RateLimitOptions
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func NewRateLimitedProvider(provider Provider, options RateLimitOptions) *RateLimitedProvider {
+	if options.Burst <= 0 {
+		options.Burst = 1
+	}
+	if options.Clock == nil {
+		options.Clock = systemClock{}
+	}
+	return &RateLimitedProvider{
+		provider: provider,
+		options:  options,
+		tokens:   float64(options.Burst),
+		last:     options.Clock.Now(),
+	}
+}
+
+// RateLimit is the ProviderMiddleware of NewRateLimitedProvider
+func RateLimit(options RateLimitOptions) ProviderMiddleware {
+	return func(provider Provider) Provider {
+		return NewRateLimitedProvider(provider, options)
+	}
+}
+
+func (r *RateLimitedProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.provider.ModVersionsWithDependencies(ctx, modID) //nolint:wrapcheck
+}
+
+func (r *RateLimitedProvider) GetModName(ctx context.Context, modReference string) (*ModName, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.provider.GetModName(ctx, modReference) //nolint:wrapcheck
+}
+
+func (r *RateLimitedProvider) SearchMods(ctx context.Context, query string) ([]ModName, error) {
+	if _, ok := r.provider.(SearchableProvider); !ok {
+		return nil, ErrSearchUnsupported
+	}
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	return searchMods(ctx, r.provider, query)
+}
+
+// wait takes a token from the bucket, sleeping until one is available.
+// Tokens are reserved before sleeping, so concurrent requests queue up instead of all waking up at once
+func (r *RateLimitedProvider) wait(ctx context.Context) error {
+	if r.options.Rate <= 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	now := r.options.Clock.Now()
+	if elapsed := now.Sub(r.last); elapsed > 0 {
+		r.tokens = min(float64(r.options.Burst), r.tokens+elapsed.Seconds()*r.options.Rate)
+		r.last = now
+	}
+	r.tokens--
+	delay := time.Duration(-r.tokens / r.options.Rate * float64(time.Second))
+	r.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	if err := r.options.Clock.Sleep(ctx, delay); err != nil {
+		r.mu.Lock()
+		r.tokens++
+		r.mu.Unlock()
+		return fmt.Errorf("failed waiting for rate limit: %w", err)
+	}
+
+	return nil
+}
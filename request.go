@@ -0,0 +1,160 @@
+package resolver
+
+import (
+	"context"
+	"log/slog"
+)
+
+type ResolveRequest struct {
+	// Constraints maps mod references to the version constraint the caller wants installed
+	Constraints map[string]string
+
+	// LockFile is the previous resolution, whose versions are preferred when still allowed
+	LockFile *LockFile
+
+	GameVersion     int
+	RequiredTargets []TargetName
+
+	// VersionStrategy picks the versions of mods that are not locked, defaults to NewestVersionStrategy
+	VersionStrategy VersionStrategy
+
+	// PrereleasePolicy controls which pre-release versions may be picked,
+	// PrereleaseOverrides replaces the policy for individual mods
+	PrereleasePolicy    PrereleasePolicy
+	PrereleaseOverrides map[string]PrereleasePolicy
+
+	// Blocklist forbids mods from being installed, even as dependencies
+	Blocklist []BlockRule
+
+	// Overrides change the dependencies mods declare, and are recorded in the resulting lockfile
+	Overrides []DependencyOverride
+
+	// Upgrade unlocks only the given mods, while every other locked mod keeps its lockfile version
+	Upgrade *UpgradeRequest
+
+	Hooks  ResolveHooks
+	Logger *slog.Logger
+}
+
+// ResolveHooks are called while the solver runs.
+// Package versions are prefetched concurrently, so the hooks must be safe for concurrent use.
+type ResolveHooks struct {
+	// OnModVersions is called after the versions of a mod have been fetched from the provider
+	OnModVersions func(modID string, versions []ModVersion)
+
+	// OnVersionPicked is called every time the solver decides on a version of a mod.
+	// A mod may be picked multiple times if the solver backtracks
+	OnVersionPicked func(modID string, version string)
+}
+
+type UpgradeRequest struct {
+	ModIDs []string
+
+	// IncludeDependencies also unlocks the locked dependencies of the mods, recursively
+	IncludeDependencies bool
+}
+
+type ResolveOption func(*ResolveRequest)
+
+func WithLockFile(lockFile *LockFile) ResolveOption {
+	return func(r *ResolveRequest) {
+		r.LockFile = lockFile
+	}
+}
+
+func WithGameVersion(gameVersion int) ResolveOption {
+	return func(r *ResolveRequest) {
+		r.GameVersion = gameVersion
+	}
+}
+
+func WithRequiredTargets(targets ...TargetName) ResolveOption {
+	return func(r *ResolveRequest) {
+		r.RequiredTargets = targets
+	}
+}
+
+func WithVersionStrategy(strategy VersionStrategy) ResolveOption {
+	return func(r *ResolveRequest) {
+		r.VersionStrategy = strategy
+	}
+}
+
+func WithPrereleasePolicy(policy PrereleasePolicy) ResolveOption {
+	return func(r *ResolveRequest) {
+		r.PrereleasePolicy = policy
+	}
+}
+
+func WithModPrereleasePolicy(modID string, policy PrereleasePolicy) ResolveOption {
+	return func(r *ResolveRequest) {
+		if r.PrereleaseOverrides == nil {
+			r.PrereleaseOverrides = make(map[string]PrereleasePolicy)
+		}
+		r.PrereleaseOverrides[modID] = policy
+	}
+}
+
+func WithBlocklist(rules ...BlockRule) ResolveOption {
+	return func(r *ResolveRequest) {
+		r.Blocklist = append(r.Blocklist, rules...)
+	}
+}
+
+func WithOverrides(overrides ...DependencyOverride) ResolveOption {
+	return func(r *ResolveRequest) {
+		r.Overrides = append(r.Overrides, overrides...)
+	}
+}
+
+func WithUpgrade(includeDependencies bool, modIDs ...string) ResolveOption {
+	return func(r *ResolveRequest) {
+		r.Upgrade = &UpgradeRequest{
+			ModIDs:              modIDs,
+			IncludeDependencies: includeDependencies,
+		}
+	}
+}
+
+func WithHooks(hooks ResolveHooks) ResolveOption {
+	return func(r *ResolveRequest) {
+		r.Hooks = hooks
+	}
+}
+
+func WithLogger(logger *slog.Logger) ResolveOption {
+	return func(r *ResolveRequest) {
+		r.Logger = logger
+	}
+}
+
+func NewResolveRequest(constraints map[string]string, opts ...ResolveOption) ResolveRequest {
+	r := ResolveRequest{
+		Constraints: constraints,
+	}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+func (r ResolveRequest) versionStrategy() VersionStrategy {
+	if r.VersionStrategy == nil {
+		return NewestVersionStrategy
+	}
+	return r.VersionStrategy
+}
+
+func (r ResolveRequest) logger() *slog.Logger {
+	if r.Logger == nil {
+		return slog.New(discardHandler{})
+	}
+	return r.Logger
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
@@ -1,8 +1,10 @@
 package resolver
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/mircearoata/pubgrub-go/pubgrub"
 	"github.com/mircearoata/pubgrub-go/pubgrub/helpers"
@@ -32,13 +34,38 @@ func NewDependencyResolver(provider Provider) DependencyResolver {
 }
 
 func (d DependencyResolver) ResolveModDependencies(constraints map[string]string, lockFile *LockFile, gameVersion int, requiredTargets []TargetName) (*LockFile, error) {
-	gameVersionSemver, err := semver.NewVersion(fmt.Sprintf("%d", gameVersion))
+	return d.ResolveModDependenciesContext(context.Background(), constraints, lockFile, gameVersion, requiredTargets)
+}
+
+func (d DependencyResolver) ResolveModDependenciesContext(ctx context.Context, constraints map[string]string, lockFile *LockFile, gameVersion int, requiredTargets []TargetName) (*LockFile, error) {
+	result, err := d.Resolve(ctx, ResolveRequest{
+		Constraints:     constraints,
+		LockFile:        lockFile,
+		GameVersion:     gameVersion,
+		RequiredTargets: requiredTargets,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.LockFile, nil
+}
+
+func (d DependencyResolver) Resolve(ctx context.Context, request ResolveRequest, opts ...ResolveOption) (*ResolveResult, error) {
+	for _, opt := range opts {
+		opt(&request)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to solve dependencies: %w", err)
+	}
+
+	gameVersionSemver, err := semver.NewVersion(fmt.Sprintf("%d", request.GameVersion))
 	if err != nil {
 		return nil, fmt.Errorf("failed parsing game version: %w", err)
 	}
 
-	toInstall := make(map[string]semver.Constraint, len(constraints))
-	for k, v := range constraints {
+	toInstall := make(map[string]semver.Constraint, len(request.Constraints))
+	for k, v := range request.Constraints {
 		c, err := semver.NewConstraint(v)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse constraint %s: %w", v, err)
@@ -46,29 +73,63 @@ func (d DependencyResolver) ResolveModDependencies(constraints map[string]string
 		toInstall[k] = c
 	}
 
-	mappedTargets := make(map[TargetName]bool, len(requiredTargets))
-	for _, target := range requiredTargets {
+	mappedTargets := make(map[TargetName]bool, len(request.RequiredTargets))
+	for _, target := range request.RequiredTargets {
 		if !allTargets[target] {
 			return nil, fmt.Errorf("invalid target: %s", target)
 		}
 		mappedTargets[target] = true
 	}
 
+	blockRules, err := newBlocklist(request.Blocklist)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencyOverrides, err := newOverrides(request.Overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	var unlocked map[string]bool
+	if request.Upgrade != nil {
+		unlocked, err = d.unlockedMods(ctx, request.LockFile, *request.Upgrade)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	ficsitSource := &ficsitAPISource{
+		ctx:             ctx,
 		provider:        d.provider,
 		gameVersion:     gameVersionSemver,
-		lockfile:        lockFile,
+		lockfile:        request.LockFile,
 		toInstall:       toInstall,
 		modVersionInfo:  xsync.NewMapOf[string, []ModVersion](),
+		prefetchErrors:  make(map[string]error),
 		requiredTargets: mappedTargets,
+		hooks:           request.Hooks,
+		logger:          request.logger(),
+		strategy:        request.versionStrategy(),
+		blocklist:       blockRules,
+		overrides:       dependencyOverrides,
+		unlocked:        unlocked,
+		pinLocked:       request.Upgrade != nil,
+
+		prereleasePolicy:    request.PrereleasePolicy,
+		prereleaseOverrides: request.PrereleaseOverrides,
+	}
+
+	if batchProvider, ok := d.provider.(BatchProvider); ok {
+		ficsitSource.prefetch(batchProvider)
 	}
 
-	result, err := pubgrub.Solve(helpers.NewCachingSource(ficsitSource), rootPkg)
+	result, err := pubgrub.Solve(&contextSource{ctx: ctx, Source: helpers.NewCachingSource(ficsitSource)}, rootPkg)
 	if err != nil {
 		finalError := err
 		var solverErr pubgrub.SolvingError
 		if errors.As(err, &solverErr) {
-			finalError = DependencyResolverError{SolvingError: solverErr, provider: d.provider, gameVersion: gameVersion}
+			finalError = DependencyResolverError{SolvingError: solverErr, ctx: context.WithoutCancel(ctx), provider: d.provider, gameVersion: request.GameVersion, blocklist: blockRules, modVersions: ficsitSource.modVersionInfo}
 		}
 		return nil, fmt.Errorf("failed to solve dependencies: %w", finalError)
 	}
@@ -76,28 +137,81 @@ func (d DependencyResolver) ResolveModDependencies(constraints map[string]string
 	delete(result, rootPkg)
 	delete(result, factoryGamePkg)
 
-	outputLock := NewLockfile()
+	versions := make(map[string]ModVersion, len(result))
 	for k, v := range result {
 		value, _ := ficsitSource.modVersionInfo.Load(k)
 		for _, ver := range value {
 			if ver.Version == v.RawString() {
-				targets := make(map[string]LockedModTarget)
-				for _, target := range ver.Targets {
-					targets[string(target.TargetName)] = LockedModTarget{
-						Link: target.Link,
-						Hash: target.Hash,
-					}
-				}
-
-				outputLock.Mods[k] = LockedMod{
-					Version: v.String(),
-					Targets: targets,
-				}
-
+				versions[k] = ver
 				break
 			}
 		}
 	}
 
-	return outputLock, nil
+	resolveResult := newResolveResult(request.Constraints, result, versions)
+	resolveResult.provider = d.provider
+	resolveResult.LockFile.Overrides = dependencyOverrides.locked()
+
+	return resolveResult, nil
+}
+
+func (d DependencyResolver) unlockedMods(ctx context.Context, lockFile *LockFile, upgrade UpgradeRequest) (map[string]bool, error) {
+	unlocked := make(map[string]bool, len(upgrade.ModIDs))
+	queue := slices.Clone(upgrade.ModIDs)
+	for len(queue) > 0 {
+		modID := queue[0]
+		queue = queue[1:]
+
+		if unlocked[modID] {
+			continue
+		}
+		unlocked[modID] = true
+
+		if !upgrade.IncludeDependencies || lockFile == nil {
+			continue
+		}
+
+		locked, ok := lockFile.Mods[modID]
+		if !ok {
+			continue
+		}
+
+		dependencies, err := d.lockedDependencies(ctx, modID, locked)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, dependencies...)
+	}
+	return unlocked, nil
+}
+
+// lockedDependencies returns the dependencies of a locked mod,
+// falling back to the provider for lockfiles that did not record them
+func (d DependencyResolver) lockedDependencies(ctx context.Context, modID string, locked LockedMod) ([]string, error) {
+	if locked.Dependencies != nil {
+		dependencies := make([]string, 0, len(locked.Dependencies))
+		for dependency := range locked.Dependencies {
+			dependencies = append(dependencies, dependency)
+		}
+		slices.Sort(dependencies)
+		return dependencies, nil
+	}
+
+	versions, err := d.provider.ModVersionsWithDependencies(ctx, modID)
+	if err != nil {
+		return nil, &ProviderError{ModID: modID, Err: err}
+	}
+
+	for _, version := range versions {
+		if version.Version != locked.Version {
+			continue
+		}
+		dependencies := make([]string, 0, len(version.Dependencies))
+		for _, dependency := range version.Dependencies {
+			dependencies = append(dependencies, dependency.ModID)
+		}
+		return dependencies, nil
+	}
+
+	return nil, nil
 }
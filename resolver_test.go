@@ -1,7 +1,11 @@
 package resolver
 
 import (
+	"context"
+	"errors"
 	"math"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/MarvinJWendt/testza"
@@ -38,6 +42,26 @@ func TestResolutionNonExistentMod(t *testing.T) {
 	}, nil, math.MaxInt, nil)
 
 	testza.AssertEqual(t, "failed to solve dependencies: failed to make decision: failed to get package versions: failed to fetch mod ThisModDoesNotExist$$$: mod not found", err.Error())
+	testza.AssertTrue(t, errors.Is(err, ErrModNotFound))
+
+	var providerErr *ProviderError
+	testza.AssertTrue(t, errors.As(err, &providerErr))
+	testza.AssertEqual(t, "ThisModDoesNotExist$$$", providerErr.ModID)
+}
+
+func TestResolutionProviderUnavailable(t *testing.T) {
+	resolver := NewDependencyResolver(newFlakyProvider(1, errBadGateway))
+
+	_, err := resolver.ResolveModDependencies(map[string]string{
+		"RefinedPower": "3.2.10",
+	}, nil, math.MaxInt, nil)
+
+	testza.AssertTrue(t, errors.Is(err, ErrProviderUnavailable))
+	testza.AssertFalse(t, errors.Is(err, ErrModNotFound))
+
+	var providerErr *ProviderError
+	testza.AssertTrue(t, errors.As(err, &providerErr))
+	testza.AssertEqual(t, "RefinedPower", providerErr.ModID)
 }
 
 func TestInvalidConstraint(t *testing.T) {
@@ -62,6 +86,31 @@ And because Satisfactory Mod Loader (SML) ">=3.6.0" depends on Satisfactory (Fac
 So, because Satisfactory CL0 is installed, version solving failed.`, err.Error())
 }
 
+type contextNamesProvider struct {
+	MockProvider
+}
+
+func (p contextNamesProvider) GetModName(ctx context.Context, modReference string) (*ModName, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.MockProvider.GetModName(ctx, modReference)
+}
+
+func TestErrorAfterCancel(t *testing.T) {
+	resolver := NewDependencyResolver(contextNamesProvider{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := resolver.Resolve(ctx, NewResolveRequest(map[string]string{
+		"RefinedPower": "*",
+	}))
+	cancel()
+
+	var resolverErr DependencyResolverError
+	testza.AssertTrue(t, errors.As(err, &resolverErr))
+	testza.AssertContains(t, resolverErr.Error(), "Refined Power (RefinedPower)")
+}
+
 func TestLockfileResolution(t *testing.T) {
 	resolver := NewDependencyResolver(MockProvider{})
 
@@ -155,3 +204,422 @@ func TestNoMatchForAllTargetsNotRequiredOnRemote(t *testing.T) {
 
 	testza.AssertEqual(t, "failed to solve dependencies: So, because installing ServerOnlyMod \"2.0.0\" and ServerOnlyMod \"2.0.0\" is forbidden, version solving failed.", err.Error())
 }
+
+type contextKey struct{}
+
+type contextRecordingProvider struct {
+	MockProvider
+	mu     sync.Mutex
+	values []any
+	onCall func()
+}
+
+func (p *contextRecordingProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	p.mu.Lock()
+	p.values = append(p.values, ctx.Value(contextKey{}))
+	onCall := p.onCall
+	p.mu.Unlock()
+	if onCall != nil {
+		onCall()
+	}
+	return p.MockProvider.ModVersionsWithDependencies(ctx, modID)
+}
+
+func TestResolutionContextValues(t *testing.T) {
+	provider := &contextRecordingProvider{}
+	resolver := NewDependencyResolver(provider)
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "request")
+	_, err := resolver.ResolveModDependenciesContext(ctx, map[string]string{
+		"RefinedPower": "3.2.10",
+	}, nil, math.MaxInt, nil)
+
+	testza.AssertNoError(t, err)
+	testza.AssertGreater(t, len(provider.values), 0)
+	for _, v := range provider.values {
+		testza.AssertEqual(t, "request", v)
+	}
+}
+
+func TestResolutionCancelledContext(t *testing.T) {
+	resolver := NewDependencyResolver(MockProvider{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := resolver.ResolveModDependenciesContext(ctx, map[string]string{
+		"RefinedPower": "3.2.10",
+	}, nil, math.MaxInt, nil)
+
+	testza.AssertTrue(t, errors.Is(err, context.Canceled))
+}
+
+func TestResolutionCancelledDuringSolve(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider := &contextRecordingProvider{onCall: cancel}
+	resolver := NewDependencyResolver(provider)
+
+	_, err := resolver.ResolveModDependenciesContext(ctx, map[string]string{
+		"RefinedPower": "3.2.10",
+	}, nil, math.MaxInt, nil)
+
+	testza.AssertTrue(t, errors.Is(err, context.Canceled))
+}
+
+func TestResolveWithOptions(t *testing.T) {
+	resolver := NewDependencyResolver(MockProvider{})
+
+	lockfile := NewLockfile()
+	lockfile.Mods["RefinedPower"] = LockedMod{
+		Version: "3.2.11",
+	}
+
+	var mu sync.Mutex
+	fetched := map[string]bool{}
+	picked := map[string]string{}
+
+	resolved, err := resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": ">=3.2.10",
+	}), WithLockFile(lockfile), WithGameVersion(math.MaxInt), WithRequiredTargets(TargetNameWindows), WithHooks(ResolveHooks{
+		OnModVersions: func(modID string, _ []ModVersion) {
+			mu.Lock()
+			defer mu.Unlock()
+			fetched[modID] = true
+		},
+		OnVersionPicked: func(modID string, version string) {
+			mu.Lock()
+			defer mu.Unlock()
+			picked[modID] = version
+		},
+	}))
+
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, resolved.Mods, 4)
+	testza.AssertEqual(t, "3.2.11", resolved.Mods["RefinedPower"].Version)
+	testza.AssertTrue(t, fetched["RefinedPower"])
+	testza.AssertEqual(t, "3.2.11", picked["RefinedPower"])
+	for modID, mod := range resolved.Mods {
+		testza.AssertEqual(t, mod.Version, picked[modID])
+	}
+}
+
+func TestResolveGraph(t *testing.T) {
+	resolver := NewDependencyResolver(MockProvider{})
+
+	result, err := resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": "3.2.10",
+	}, WithGameVersion(math.MaxInt)))
+
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, []ResolvedDependency{
+		{To: "RefinedPower", Constraint: "3.2.10"},
+	}, result.Roots)
+	testza.AssertEqual(t, []ResolvedDependency{
+		{From: "RefinedPower", To: "ModularUI", Constraint: "^2.1.9"},
+		{From: "RefinedPower", To: "RefinedRDLib", Constraint: "^1.1.5"},
+		{From: "RefinedPower", To: "SML", Constraint: "^3.6.0"},
+	}, result.Dependencies("RefinedPower"))
+	testza.AssertLen(t, result.Dependants("SML"), 3)
+	testza.AssertEqual(t, map[string]string{
+		"ModularUI":    "^2.1.9",
+		"RefinedRDLib": "^1.1.5",
+		"SML":          "^3.6.0",
+	}, result.LockFile.Mods["RefinedPower"].Dependencies)
+	testza.AssertEqual(t, map[string]string{
+		"SML": "^3.6.1",
+	}, result.LockFile.Mods["ModularUI"].Dependencies)
+}
+
+func TestWhy(t *testing.T) {
+	resolver := NewDependencyResolver(MockProvider{})
+
+	result, err := resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": "3.2.10",
+	}, WithGameVersion(math.MaxInt)))
+	testza.AssertNoError(t, err)
+
+	paths, err := result.Why(context.Background(), "ModularUI")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, paths, 1)
+	testza.AssertEqual(t, `Refined Power (RefinedPower) "3.2.10" (requested as "3.2.10") -> Modular UI (ModularUI) "2.1.12" (required as "^2.1.9")`, paths[0].String())
+
+	paths, err = result.Why(context.Background(), "SML")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, paths, 3)
+	testza.AssertEqual(t, DependencyStep{
+		ModReference: "SML",
+		Name:         "Satisfactory Mod Loader",
+		Version:      "3.6.1",
+		RequiredBy:   "ModularUI",
+		Constraint:   "^3.6.1",
+	}, paths[0][2])
+
+	_, err = result.Why(context.Background(), "ComplexMod")
+	testza.AssertEqual(t, "mod ComplexMod is not part of the resolved mods", err.Error())
+}
+
+func TestSelectiveUpgrade(t *testing.T) {
+	resolver := NewDependencyResolver(MockProvider{})
+
+	lockfile := NewLockfile()
+	lockfile.Mods["RefinedPower"] = LockedMod{
+		Version:      "3.2.10",
+		Dependencies: map[string]string{"ModularUI": "^2.1.9", "RefinedRDLib": "^1.1.5", "SML": "^3.6.0"},
+	}
+	lockfile.Mods["ModularUI"] = LockedMod{
+		Version:      "2.1.10",
+		Dependencies: map[string]string{"SML": "^3.6.0"},
+	}
+	lockfile.Mods["RefinedRDLib"] = LockedMod{
+		Version:      "1.1.5",
+		Dependencies: map[string]string{"SML": "^3.6.0"},
+	}
+	lockfile.Mods["SML"] = LockedMod{
+		Version:      "3.6.0",
+		Dependencies: map[string]string{},
+	}
+	lockfile.Mods["ComplexMod"] = LockedMod{
+		Version:      "1.0.0",
+		Dependencies: map[string]string{"SML": "^3.6.0"},
+	}
+
+	constraints := map[string]string{
+		"RefinedPower": "*",
+		"ComplexMod":   "*",
+	}
+
+	versionsOf := func(result *ResolveResult) map[string]string {
+		versions := make(map[string]string, len(result.LockFile.Mods))
+		for modID, mod := range result.LockFile.Mods {
+			versions[modID] = mod.Version
+		}
+		return versions
+	}
+
+	result, err := resolver.Resolve(context.Background(), NewResolveRequest(constraints,
+		WithLockFile(lockfile), WithGameVersion(math.MaxInt), WithUpgrade(false, "ComplexMod")))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, map[string]string{
+		"RefinedPower": "3.2.10",
+		"ModularUI":    "2.1.10",
+		"RefinedRDLib": "1.1.5",
+		"SML":          "3.6.0",
+		"ComplexMod":   "3.0.0",
+	}, versionsOf(result))
+
+	// RefinedPower cannot be upgraded while RefinedRDLib stays locked
+	result, err = resolver.Resolve(context.Background(), NewResolveRequest(constraints,
+		WithLockFile(lockfile), WithGameVersion(math.MaxInt), WithUpgrade(false, "RefinedPower")))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.2.10", result.LockFile.Mods["RefinedPower"].Version)
+
+	result, err = resolver.Resolve(context.Background(), NewResolveRequest(constraints,
+		WithLockFile(lockfile), WithGameVersion(math.MaxInt), WithUpgrade(true, "RefinedPower")))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, map[string]string{
+		"RefinedPower": "3.2.13",
+		"ModularUI":    "2.1.12",
+		"RefinedRDLib": "1.1.7",
+		"SML":          "3.6.1",
+		"ComplexMod":   "1.0.0",
+	}, versionsOf(result))
+}
+
+func TestBlocklist(t *testing.T) {
+	resolver := NewDependencyResolver(MockProvider{})
+
+	result, err := resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": "*",
+	}, WithGameVersion(math.MaxInt), WithBlocklist(BlockRule{ModID: "ModularUI", Constraint: ">=2.1.12"})))
+
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "2.1.11", result.LockFile.Mods["ModularUI"].Version)
+
+	_, err = resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": "3.2.10",
+	}, WithGameVersion(math.MaxInt), WithBlocklist(BlockRule{ModID: "ModularUI", Reason: "banned on this server"})))
+
+	testza.AssertEqual(t, `failed to solve dependencies: Because Refined Power (RefinedPower) "3.2.10" depends on Modular UI (ModularUI) "^2.1.9" and Modular UI (ModularUI) "^2.1.9" is blocked by rule ModularUI (banned on this server), Refined Power (RefinedPower) "3.2.10" is forbidden.
+So, because installing Refined Power (RefinedPower) "3.2.10", version solving failed.`, err.Error())
+}
+
+func TestBlocklistOtherReasons(t *testing.T) {
+	provider := staticProvider{versions: map[string][]ModVersion{
+		"ServerMod": {
+			{Version: "1.0.0", Targets: []Target{{TargetName: TargetNameWindows}}},
+			{Version: "2.0.0", Targets: []Target{{TargetName: TargetNameWindows}, {TargetName: TargetNameLinuxServer}}},
+		},
+	}}
+	resolver := NewDependencyResolver(provider)
+
+	// 1.0.0 is excluded by the target, not by the rule, so the rule must not be blamed
+	_, err := resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"ServerMod": "*",
+	}, WithGameVersion(math.MaxInt), WithRequiredTargets(TargetNameLinuxServer), WithBlocklist(BlockRule{ModID: "ServerMod", Constraint: "2.0.0"})))
+	testza.AssertNotNil(t, err)
+	testza.AssertFalse(t, strings.Contains(err.Error(), "blocked by rule"))
+
+	_, err = resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"ServerMod": "*",
+	}, WithGameVersion(math.MaxInt), WithBlocklist(BlockRule{ModID: "ServerMod", Constraint: ">=1.0.0"})))
+	testza.AssertNotNil(t, err)
+	testza.AssertTrue(t, strings.Contains(err.Error(), `is blocked by rule ServerMod ">=1.0.0"`))
+
+	_, err = resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"ServerMod": "*",
+	}, WithBlocklist(BlockRule{Constraint: "2.0.0"})))
+	testza.AssertEqual(t, "block rule is missing the mod id", err.Error())
+}
+
+func TestDependencyOverrides(t *testing.T) {
+	resolver := NewDependencyResolver(MockProvider{})
+
+	result, err := resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": "3.2.13",
+	}, WithGameVersion(math.MaxInt), WithBlocklist(BlockRule{ModID: "SML", Constraint: "3.6.1"}), WithOverrides(
+		DependencyOverride{ModID: "SML", Condition: "^3.6.0"},
+		DependencyOverride{ModID: "ModularUI", ReplaceWith: "ComplexMod", Condition: "^2.0.0"},
+	)))
+
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.6.0", result.LockFile.Mods["SML"].Version)
+	testza.AssertEqual(t, "2.0.0", result.LockFile.Mods["ComplexMod"].Version)
+	testza.AssertFalse(t, func() bool {
+		_, ok := result.LockFile.Mods["ModularUI"]
+		return ok
+	}())
+	testza.AssertEqual(t, map[string]string{
+		"ComplexMod":   "^2.0.0",
+		"RefinedRDLib": "^1.1.7",
+		"SML":          "^3.6.0",
+	}, result.LockFile.Mods["RefinedPower"].Dependencies)
+	testza.AssertEqual(t, map[string]LockedOverride{
+		"SML":       {Condition: "^3.6.0"},
+		"ModularUI": {Condition: "^2.0.0", ReplaceWith: "ComplexMod"},
+	}, result.LockFile.Overrides)
+
+	_, err = resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": "3.2.13",
+	}, WithOverrides(DependencyOverride{ModID: "SML"})))
+	testza.AssertEqual(t, "dependency override for SML must set a condition or a replacement", err.Error())
+}
+
+func TestDependencyOverrideDuplicates(t *testing.T) {
+	resolver := NewDependencyResolver(MockProvider{})
+
+	// RefinedPower 3.2.13 already depends on SML "^3.6.1", so replacing ModularUI with SML must keep both conditions,
+	// which here cannot both be satisfied
+	overrides, err := newOverrides([]DependencyOverride{{ModID: "ModularUI", ReplaceWith: "SML", Condition: "3.6.0"}})
+	testza.AssertNoError(t, err)
+	versions, _ := MockProvider{}.ModVersionsWithDependencies(context.Background(), "RefinedPower")
+	testza.AssertEqual(t, []Dependency{
+		{ModID: "SML", Condition: ""},
+		{ModID: "RefinedRDLib", Condition: "^1.1.7"},
+	}, overrides.apply(versions)[0].Dependencies)
+
+	_, err = resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": "3.2.13",
+	}, WithGameVersion(math.MaxInt), WithOverrides(DependencyOverride{ModID: "ModularUI", ReplaceWith: "SML", Condition: "3.6.0"})))
+	testza.AssertNotNil(t, err)
+
+	result, err := resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": "3.2.13",
+	}, WithGameVersion(math.MaxInt), WithOverrides(DependencyOverride{ModID: "ModularUI", ReplaceWith: "SML", Condition: ">=3.6.0"})))
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.6.1", result.LockFile.Mods["SML"].Version)
+	testza.AssertEqual(t, "^3.6.1", result.LockFile.Mods["RefinedPower"].Dependencies["SML"])
+}
+
+func TestIncompatibilities(t *testing.T) {
+	resolver := NewDependencyResolver(MockProvider{})
+
+	resolved, err := resolver.ResolveModDependencies(map[string]string{
+		"MapOverhaul": "*",
+		"ComplexMod":  "*",
+	}, nil, math.MaxInt, nil)
+
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "1.0.0", resolved.Mods["ComplexMod"].Version)
+
+	_, err = resolver.ResolveModDependencies(map[string]string{
+		"MapOverhaul": "*",
+		"ComplexMod":  ">=2.0.0",
+	}, nil, math.MaxInt, nil)
+
+	testza.AssertEqual(t, `failed to solve dependencies: Because installing ComplexMod ">=2.0.0" and every version of Map Overhaul (MapOverhaul) conflicts with ComplexMod ">=2.0.0", every version of Map Overhaul (MapOverhaul) is forbidden.
+So, because installing every version of Map Overhaul (MapOverhaul), version solving failed.`, err.Error())
+}
+
+// onceProvider only answers the first request for each mod, like a provider whose data changes during the resolution
+type onceProvider struct {
+	MockProvider
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func (p *onceProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.seen[modID] {
+		return nil, ErrProviderUnavailable
+	}
+	p.seen[modID] = true
+	return p.MockProvider.ModVersionsWithDependencies(ctx, modID)
+}
+
+func TestIncompatibilitiesUseSolverVersions(t *testing.T) {
+	resolver := NewDependencyResolver(&onceProvider{seen: map[string]bool{}})
+
+	_, err := resolver.ResolveModDependencies(map[string]string{
+		"MapOverhaul": "*",
+		"ComplexMod":  ">=2.0.0",
+	}, nil, math.MaxInt, nil)
+
+	testza.AssertTrue(t, strings.Contains(err.Error(), "every version of Map Overhaul (MapOverhaul) conflicts with ComplexMod"))
+}
+
+func TestForbiddenOnlyForConflicts(t *testing.T) {
+	provider := staticProvider{versions: map[string][]ModVersion{
+		"A": {{Version: "1.0.0", Dependencies: []Dependency{{ModID: "B", Condition: "^1.0.0", Optional: true}}}},
+		"B": {{Version: "1.0.0"}, {Version: "2.0.0"}},
+	}}
+
+	_, err := NewDependencyResolver(provider).ResolveModDependencies(map[string]string{
+		"A": "*",
+		"B": ">=2.0.0",
+	}, nil, math.MaxInt, nil)
+
+	testza.AssertEqual(t, `failed to solve dependencies: Because installing every version of A and every version of A depends on B "1.0.0", installing B "1.0.0".
+So, because installing B "2.0.0", version solving failed.`, err.Error())
+}
+
+func TestLockfileMetadata(t *testing.T) {
+	provider := staticProvider{versions: map[string][]ModVersion{
+		"ClientOnlyMod": {
+			{
+				Version:          "1.0.0",
+				GameVersion:      ">=264901",
+				RequiredOnRemote: false,
+				Targets: []Target{
+					{TargetName: TargetNameWindows, Hash: "abc", Link: "https://example.com/ClientOnlyMod.zip", Size: 4096},
+				},
+			},
+		},
+	}}
+
+	lockFile, err := NewDependencyResolver(provider).ResolveModDependencies(map[string]string{
+		"ClientOnlyMod": "1.0.0",
+	}, nil, math.MaxInt, nil)
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, LockedMod{
+		Version:      "1.0.0",
+		Dependencies: map[string]string{},
+		Targets: map[string]LockedModTarget{
+			"Windows": {Hash: "abc", Link: "https://example.com/ClientOnlyMod.zip", Size: 4096},
+		},
+		RequiredOnRemote: false,
+		GameVersion:      ">=264901",
+	}, lockFile.Mods["ClientOnlyMod"])
+}
@@ -0,0 +1,140 @@
+package resolver
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+type ResolveResult struct {
+	LockFile *LockFile
+
+	// Mods contains every resolved mod, keyed by mod reference
+	Mods map[string]ResolvedMod
+
+	// Roots are the edges from the requested constraints to the mods they require
+	Roots []ResolvedDependency
+
+	provider Provider
+}
+
+type ResolvedMod struct {
+	ModReference string
+	Version      string
+	ModVersion   ModVersion
+
+	// Dependencies are the edges to the other resolved mods this version depends on.
+	// Optional dependencies are only included if the dependency was installed
+	Dependencies []ResolvedDependency
+}
+
+type ResolvedDependency struct {
+	// From is the dependant mod reference, or empty for a requested constraint
+	From       string
+	To         string
+	Constraint string
+	Optional   bool
+}
+
+func (r *ResolveResult) Dependencies(modReference string) []ResolvedDependency {
+	return r.Mods[modReference].Dependencies
+}
+
+// Dependants returns the edges pointing to the given mod, including the requested constraint if there is one
+func (r *ResolveResult) Dependants(modReference string) []ResolvedDependency {
+	var result []ResolvedDependency
+	for _, root := range r.Roots {
+		if root.To == modReference {
+			result = append(result, root)
+		}
+	}
+	for _, mod := range r.sortedMods() {
+		for _, dep := range mod.Dependencies {
+			if dep.To == modReference {
+				result = append(result, dep)
+			}
+		}
+	}
+	return result
+}
+
+func (r *ResolveResult) sortedMods() []ResolvedMod {
+	mods := make([]ResolvedMod, 0, len(r.Mods))
+	for _, mod := range r.Mods {
+		mods = append(mods, mod)
+	}
+	slices.SortFunc(mods, func(a, b ResolvedMod) int {
+		return strings.Compare(a.ModReference, b.ModReference)
+	})
+	return mods
+}
+
+func newResolveResult(constraints map[string]string, decisions map[string]semver.Version, versions map[string]ModVersion) *ResolveResult {
+	result := &ResolveResult{
+		LockFile: NewLockfile(),
+		Mods:     make(map[string]ResolvedMod, len(versions)),
+	}
+
+	for modReference, constraint := range constraints {
+		result.Roots = append(result.Roots, ResolvedDependency{
+			To:         modReference,
+			Constraint: constraint,
+		})
+	}
+	sortDependencies(result.Roots)
+
+	for modReference, modVersion := range versions {
+		var dependencies []ResolvedDependency
+		for _, dependency := range modVersion.Dependencies {
+			if _, ok := versions[dependency.ModID]; !ok {
+				// Optional dependency that was not installed
+				continue
+			}
+			dependencies = append(dependencies, ResolvedDependency{
+				From:       modReference,
+				To:         dependency.ModID,
+				Constraint: dependency.Condition,
+				Optional:   dependency.Optional,
+			})
+		}
+		sortDependencies(dependencies)
+
+		result.Mods[modReference] = ResolvedMod{
+			ModReference: modReference,
+			Version:      decisions[modReference].String(),
+			ModVersion:   modVersion,
+			Dependencies: dependencies,
+		}
+
+		targets := make(map[string]LockedModTarget)
+		for _, target := range modVersion.Targets {
+			targets[string(target.TargetName)] = LockedModTarget{
+				Link: target.Link,
+				Hash: target.Hash,
+				Size: target.Size,
+			}
+		}
+
+		lockedDependencies := make(map[string]string, len(dependencies))
+		for _, dependency := range dependencies {
+			lockedDependencies[dependency.To] = dependency.Constraint
+		}
+
+		result.LockFile.Mods[modReference] = LockedMod{
+			Version:          decisions[modReference].String(),
+			Targets:          targets,
+			Dependencies:     lockedDependencies,
+			RequiredOnRemote: modVersion.RequiredOnRemote,
+			GameVersion:      modVersion.GameVersion,
+		}
+	}
+
+	return result
+}
+
+func sortDependencies(dependencies []ResolvedDependency) {
+	slices.SortFunc(dependencies, func(a, b ResolvedDependency) int {
+		return strings.Compare(a.To, b.To)
+	})
+}
@@ -0,0 +1,112 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 500 * time.Millisecond
+	DefaultRetryMaxDelay    = 10 * time.Second
+)
+
+type RetryOptions struct {
+	// MaxAttempts is the total number of attempts of a request, including the first one,
+	// defaults to DefaultRetryMaxAttempts
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry, doubled for every following retry,
+	// defaults to DefaultRetryBaseDelay
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between retries, defaults to DefaultRetryMaxDelay
+	MaxDelay time.Duration
+
+	// Retryable decides which errors are retried, defaults to IsTransientError
+	Retryable func(error) bool
+
+	Clock Clock
+}
+
+var _ SearchableProvider = (*RetryingProvider)(nil)
+
+// RetryingProvider retries the failed requests of another Provider with exponential backoff
+type RetryingProvider struct {
+	provider Provider
+	options  RetryOptions
+}
+
+func NewRetryingProvider(provider Provider, options RetryOptions) *RetryingProvider {
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if options.BaseDelay == 0 {
+		options.BaseDelay = DefaultRetryBaseDelay
+	}
+	if options.MaxDelay == 0 {
+		options.MaxDelay = DefaultRetryMaxDelay
+	}
+	if options.Retryable == nil {
+		options.Retryable = IsTransientError
+	}
+	if options.Clock == nil {
+		options.Clock = systemClock{}
+	}
+	return &RetryingProvider{
+		provider: provider,
+		options:  options,
+	}
+}
+
+// Retry is the ProviderMiddleware of NewRetryingProvider
+func Retry(options RetryOptions) ProviderMiddleware {
+	return func(provider Provider) Provider {
+		return NewRetryingProvider(provider, options)
+	}
+}
+
+func (r *RetryingProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	return retry(ctx, r, func() ([]ModVersion, error) {
+		return r.provider.ModVersionsWithDependencies(ctx, modID) //nolint:wrapcheck
+	})
+}
+
+func (r *RetryingProvider) GetModName(ctx context.Context, modReference string) (*ModName, error) {
+	return retry(ctx, r, func() (*ModName, error) {
+		return r.provider.GetModName(ctx, modReference) //nolint:wrapcheck
+	})
+}
+
+func (r *RetryingProvider) SearchMods(ctx context.Context, query string) ([]ModName, error) {
+	return retry(ctx, r, func() ([]ModName, error) {
+		return searchMods(ctx, r.provider, query)
+	})
+}
+
+// delay returns how long to wait before the given retry, starting from 1
+func (r *RetryingProvider) delay(retry int) time.Duration {
+	delay := r.options.BaseDelay
+	for i := 1; i < retry && delay < r.options.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, r.options.MaxDelay)
+}
+
+func retry[T any](ctx context.Context, r *RetryingProvider, request func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		value, err := request()
+		if err == nil || !r.options.Retryable(err) {
+			return value, err
+		}
+
+		if attempt == r.options.MaxAttempts {
+			return value, fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		if sleepErr := r.options.Clock.Sleep(ctx, r.delay(attempt)); sleepErr != nil {
+			return value, fmt.Errorf("retry interrupted: %w (last error: %w)", sleepErr, err)
+		}
+	}
+}
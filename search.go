@@ -0,0 +1,111 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// SearchableProvider is a Provider that can list the mods it knows about.
+// When a mod is not found, the resolver uses it to suggest the closest mod references.
+// The provider wrappers of this package pass searches through to the providers they wrap
+type SearchableProvider interface {
+	Provider
+
+	// SearchMods returns the mods matching the query, or every mod if the query is empty.
+	// Providers may return more mods than match, the results are ranked by the caller
+	SearchMods(ctx context.Context, query string) ([]ModName, error)
+}
+
+// ErrSearchUnsupported is returned by the SearchMods of provider wrappers, such as CachingProvider,
+// when the provider they wrap is not a SearchableProvider
+var ErrSearchUnsupported = errors.New("provider does not support search")
+
+// searchMods searches the provider, if it is a SearchableProvider
+func searchMods(ctx context.Context, provider Provider, query string) ([]ModName, error) {
+	searchable, ok := provider.(SearchableProvider)
+	if !ok {
+		return nil, ErrSearchUnsupported
+	}
+	return searchable.SearchMods(ctx, query) //nolint:wrapcheck
+}
+
+// maxModSuggestions is the maximum number of suggestions for a mod that was not found
+const maxModSuggestions = 3
+
+// suggestMods returns the mod references closest to the given one by edit distance,
+// comparing against both the mod references and the names of the mods
+func suggestMods(ctx context.Context, provider SearchableProvider, modReference string) []string {
+	mods, err := provider.SearchMods(ctx, modReference)
+	if err != nil {
+		// Suggestions are best effort, the not found error is what matters
+		return nil
+	}
+
+	query := strings.ToLower(modReference)
+	// Allow roughly one typo every three characters, so that short references do not match everything
+	maxDistance := max(1, len([]rune(query))/3)
+
+	type suggestion struct {
+		modReference string
+		distance     int
+	}
+	var suggestions []suggestion
+	for _, mod := range mods {
+		if mod.ModReference == modReference {
+			continue
+		}
+		distance := min(
+			editDistance(query, strings.ToLower(mod.ModReference)),
+			editDistance(query, strings.ToLower(mod.Name)),
+		)
+		if distance > maxDistance {
+			continue
+		}
+		suggestions = append(suggestions, suggestion{modReference: mod.ModReference, distance: distance})
+	}
+
+	slices.SortFunc(suggestions, func(a, b suggestion) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.modReference, b.modReference)
+	})
+
+	result := make([]string, 0, min(len(suggestions), maxModSuggestions))
+	for _, s := range suggestions {
+		if len(result) == maxModSuggestions {
+			break
+		}
+		if !slices.Contains(result, s.modReference) {
+			result = append(result, s.modReference)
+		}
+	}
+	return result
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a string, b string) int {
+	ar, br := []rune(a), []rune(b)
+
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(br)]
+}
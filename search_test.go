@@ -0,0 +1,91 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+func TestEditDistance(t *testing.T) {
+	testza.AssertEqual(t, 0, editDistance("RefinedPower", "RefinedPower"))
+	testza.AssertEqual(t, 1, editDistance("RefinedPowr", "RefinedPower"))
+	testza.AssertEqual(t, 2, editDistance("RefinedPwoer", "RefinedPower"))
+	testza.AssertEqual(t, 3, editDistance("", "SML"))
+	testza.AssertEqual(t, 3, editDistance("kitten", "sitting"))
+}
+
+func TestModSuggestions(t *testing.T) {
+	ctx := context.Background()
+
+	testza.AssertEqual(t, []string{"RefinedPower"}, suggestMods(ctx, MockProvider{}, "RefinedPowr"))
+	testza.AssertEqual(t, []string{"RefinedPower"}, suggestMods(ctx, MockProvider{}, "refinedpower"))
+	// Names are matched too
+	testza.AssertEqual(t, []string{"ModularUI"}, suggestMods(ctx, MockProvider{}, "Modular-UI"))
+	testza.AssertEqual(t, []string{"ClientOnlyMod"}, suggestMods(ctx, MockProvider{}, "ClientOnlyMods"))
+	testza.AssertLen(t, suggestMods(ctx, MockProvider{}, "SomethingElse"), 0)
+}
+
+func TestResolutionModSuggestions(t *testing.T) {
+	resolver := NewDependencyResolver(MockProvider{})
+
+	_, err := resolver.ResolveModDependencies(map[string]string{
+		"RefinedPowr": ">0.0.0",
+	}, nil, math.MaxInt, nil)
+
+	testza.AssertEqual(t, "failed to solve dependencies: failed to make decision: failed to get package versions: failed to fetch mod RefinedPowr: mod not found (did you mean RefinedPower?)", err.Error())
+	testza.AssertTrue(t, errors.Is(err, ErrModNotFound))
+
+	var providerErr *ProviderError
+	testza.AssertTrue(t, errors.As(err, &providerErr))
+	testza.AssertEqual(t, []string{"RefinedPower"}, providerErr.Suggestions)
+}
+
+func TestDirectoryProviderSearch(t *testing.T) {
+	dir := t.TempDir()
+	testza.AssertNoError(t, ExportModIndex(context.Background(), MockProvider{}, dir, "RefinedPower"))
+
+	provider := NewDirectoryProvider(dir)
+	mods, err := provider.SearchMods(context.Background(), "")
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, mods, 4)
+
+	_, err = NewDependencyResolver(provider).ResolveModDependencies(map[string]string{
+		"ModularUl": ">0.0.0",
+	}, nil, math.MaxInt, nil)
+	testza.AssertEqual(t, "failed to solve dependencies: failed to make decision: failed to get package versions: failed to fetch mod ModularUl: mod not found (did you mean ModularUI?)", err.Error())
+}
+
+func TestWrappedProviderSuggestions(t *testing.T) {
+	providers := map[string]Provider{
+		"caching":   NewCachingProvider(MockProvider{}, CachingProviderOptions{}),
+		"recording": NewRecordingProvider(MockProvider{}),
+		"layered": NewLayeredProvider(
+			ProviderLayer{Name: "local", Provider: staticProvider{}},
+			ProviderLayer{Name: "remote", Provider: MockProvider{}},
+		),
+		"middleware": ChainProvider(MockProvider{},
+			Retry(RetryOptions{}),
+			RateLimit(RateLimitOptions{}),
+			CircuitBreaker(CircuitBreakerOptions{}),
+		),
+	}
+
+	for name, provider := range providers {
+		_, err := NewDependencyResolver(provider).ResolveModDependencies(map[string]string{
+			"RefinedPowr": ">0.0.0",
+		}, nil, math.MaxInt, nil)
+
+		var providerErr *ProviderError
+		testza.AssertTrue(t, errors.As(err, &providerErr), name)
+		testza.AssertEqual(t, []string{"RefinedPower"}, providerErr.Suggestions, name)
+	}
+
+	_, err := NewCachingProvider(staticProvider{}, CachingProviderOptions{}).SearchMods(context.Background(), "")
+	testza.AssertTrue(t, errors.Is(err, ErrSearchUnsupported))
+
+	_, err = NewLayeredProvider(ProviderLayer{Name: "local", Provider: staticProvider{}}).SearchMods(context.Background(), "")
+	testza.AssertTrue(t, errors.Is(err, ErrSearchUnsupported))
+}
@@ -0,0 +1,221 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"sync"
+)
+
+const providerSnapshotVersion = 1
+
+var ErrNotRecorded = errors.New("query was not recorded in the snapshot")
+
+// ProviderSnapshot contains every provider response captured by a RecordingProvider
+type ProviderSnapshot struct {
+	Version int                         `json:"version"`
+	Mods    map[string]SnapshotVersions `json:"mods"`
+	Names   map[string]SnapshotName     `json:"names"`
+}
+
+type SnapshotVersions struct {
+	Versions  []ModVersion      `json:"versions,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	ErrorKind SnapshotErrorKind `json:"error_kind,omitempty"`
+}
+
+type SnapshotName struct {
+	Name      *ModName          `json:"name,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	ErrorKind SnapshotErrorKind `json:"error_kind,omitempty"`
+}
+
+// SnapshotErrorKind records which typed error a recorded error matched,
+// so that replayed errors still work with errors.Is
+type SnapshotErrorKind string
+
+const (
+	SnapshotModNotFound         SnapshotErrorKind = "mod_not_found"
+	SnapshotProviderUnavailable SnapshotErrorKind = "provider_unavailable"
+)
+
+func snapshotErrorKind(err error) SnapshotErrorKind {
+	switch {
+	case errors.Is(err, ErrModNotFound):
+		return SnapshotModNotFound
+	case errors.Is(err, ErrProviderUnavailable):
+		return SnapshotProviderUnavailable
+	default:
+		return ""
+	}
+}
+
+// replayedError has the message of the recorded error, and wraps the typed error of its kind
+type replayedError struct {
+	message string
+	kind    SnapshotErrorKind
+}
+
+func (e *replayedError) Error() string {
+	return e.message
+}
+
+func (e *replayedError) Unwrap() error {
+	switch e.kind {
+	case SnapshotModNotFound:
+		return ErrModNotFound
+	case SnapshotProviderUnavailable:
+		return ErrProviderUnavailable
+	default:
+		return nil
+	}
+}
+
+func NewProviderSnapshot() *ProviderSnapshot {
+	return &ProviderSnapshot{
+		Version: providerSnapshotVersion,
+		Mods:    make(map[string]SnapshotVersions),
+		Names:   make(map[string]SnapshotName),
+	}
+}
+
+func ReadProviderSnapshot(r io.Reader) (*ProviderSnapshot, error) {
+	var snapshot ProviderSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode provider snapshot: %w", err)
+	}
+	if snapshot.Version != providerSnapshotVersion {
+		return nil, fmt.Errorf("unsupported provider snapshot version %d", snapshot.Version)
+	}
+	if snapshot.Mods == nil {
+		snapshot.Mods = make(map[string]SnapshotVersions)
+	}
+	if snapshot.Names == nil {
+		snapshot.Names = make(map[string]SnapshotName)
+	}
+	return &snapshot, nil
+}
+
+func (s *ProviderSnapshot) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode provider snapshot: %w", err)
+	}
+	n, err := w.Write(append(data, '\n'))
+	if err != nil {
+		return int64(n), fmt.Errorf("failed to write provider snapshot: %w", err)
+	}
+	return int64(n), nil
+}
+
+var _ SearchableProvider = (*RecordingProvider)(nil)
+
+// RecordingProvider captures every response of another Provider, so that a resolution can be replayed later
+type RecordingProvider struct {
+	provider Provider
+
+	mu       sync.Mutex
+	snapshot *ProviderSnapshot
+}
+
+func NewRecordingProvider(provider Provider) *RecordingProvider {
+	return &RecordingProvider{
+		provider: provider,
+		snapshot: NewProviderSnapshot(),
+	}
+}
+
+func (r *RecordingProvider) ModVersionsWithDependencies(ctx context.Context, modID string) ([]ModVersion, error) {
+	versions, err := r.provider.ModVersionsWithDependencies(ctx, modID)
+	if isContextError(ctx, err) {
+		return nil, err //nolint:wrapcheck
+	}
+
+	entry := SnapshotVersions{Versions: versions}
+	if err != nil {
+		entry = SnapshotVersions{Error: err.Error(), ErrorKind: snapshotErrorKind(err)}
+	}
+
+	r.mu.Lock()
+	r.snapshot.Mods[modID] = entry
+	r.mu.Unlock()
+
+	return versions, err //nolint:wrapcheck
+}
+
+func (r *RecordingProvider) GetModName(ctx context.Context, modReference string) (*ModName, error) {
+	name, err := r.provider.GetModName(ctx, modReference)
+	if isContextError(ctx, err) {
+		return nil, err //nolint:wrapcheck
+	}
+
+	entry := SnapshotName{Name: name}
+	if err != nil {
+		entry = SnapshotName{Error: err.Error(), ErrorKind: snapshotErrorKind(err)}
+	}
+
+	r.mu.Lock()
+	r.snapshot.Names[modReference] = entry
+	r.mu.Unlock()
+
+	return name, err //nolint:wrapcheck
+}
+
+// SearchMods searches the wrapped provider. Searches are not recorded, so a replay has no suggestions for missing mods
+func (r *RecordingProvider) SearchMods(ctx context.Context, query string) ([]ModName, error) {
+	return searchMods(ctx, r.provider, query)
+}
+
+// Snapshot returns a copy of the responses recorded so far
+func (r *RecordingProvider) Snapshot() *ProviderSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &ProviderSnapshot{
+		Version: r.snapshot.Version,
+		Mods:    maps.Clone(r.snapshot.Mods),
+		Names:   maps.Clone(r.snapshot.Names),
+	}
+}
+
+// isContextError checks if err was caused by the cancellation of ctx, rather than being a response of the provider
+func isContextError(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err())
+}
+
+var _ Provider = (*ReplayProvider)(nil)
+
+// ReplayProvider answers only from a snapshot, and returns ErrNotRecorded for any other query
+type ReplayProvider struct {
+	snapshot *ProviderSnapshot
+}
+
+func NewReplayProvider(snapshot *ProviderSnapshot) *ReplayProvider {
+	return &ReplayProvider{
+		snapshot: snapshot,
+	}
+}
+
+func (r *ReplayProvider) ModVersionsWithDependencies(_ context.Context, modID string) ([]ModVersion, error) {
+	entry, ok := r.snapshot.Mods[modID]
+	if !ok {
+		return nil, fmt.Errorf("versions of mod %s: %w", modID, ErrNotRecorded)
+	}
+	if entry.Error != "" {
+		return nil, &replayedError{message: entry.Error, kind: entry.ErrorKind}
+	}
+	return entry.Versions, nil
+}
+
+func (r *ReplayProvider) GetModName(_ context.Context, modReference string) (*ModName, error) {
+	entry, ok := r.snapshot.Names[modReference]
+	if !ok {
+		return nil, fmt.Errorf("name of mod %s: %w", modReference, ErrNotRecorded)
+	}
+	if entry.Error != "" {
+		return nil, &replayedError{message: entry.Error, kind: entry.ErrorKind}
+	}
+	return entry.Name, nil
+}
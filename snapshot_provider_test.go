@@ -0,0 +1,55 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+func TestRecordReplayProvider(t *testing.T) {
+	recording := NewRecordingProvider(MockProvider{})
+
+	_, err := NewDependencyResolver(recording).ResolveModDependencies(map[string]string{
+		"RefinedPower": "3.2.11",
+		"RefinedRDLib": "1.1.5",
+	}, nil, math.MaxInt, nil)
+	testza.AssertNotNil(t, err)
+	// Rendering the error also queries the provider for the mod names
+	expectedError := err.Error()
+
+	_, err = NewDependencyResolver(recording).ResolveModDependencies(map[string]string{
+		"ThisModDoesNotExist$$$": "*",
+	}, nil, math.MaxInt, nil)
+	testza.AssertNotNil(t, err)
+
+	var buf bytes.Buffer
+	_, err = recording.Snapshot().WriteTo(&buf)
+	testza.AssertNoError(t, err)
+
+	snapshot, err := ReadProviderSnapshot(&buf)
+	testza.AssertNoError(t, err)
+	replay := NewReplayProvider(snapshot)
+
+	_, err = NewDependencyResolver(replay).ResolveModDependencies(map[string]string{
+		"RefinedPower": "3.2.11",
+		"RefinedRDLib": "1.1.5",
+	}, nil, math.MaxInt, nil)
+	testza.AssertEqual(t, expectedError, err.Error())
+
+	_, err = replay.ModVersionsWithDependencies(context.Background(), "ThisModDoesNotExist$$$")
+	testza.AssertEqual(t, "mod not found", err.Error())
+	testza.AssertTrue(t, errors.Is(err, ErrModNotFound))
+
+	_, err = replay.ModVersionsWithDependencies(context.Background(), "ComplexMod")
+	testza.AssertTrue(t, errors.Is(err, ErrNotRecorded))
+
+	_, err = replay.GetModName(context.Background(), "ComplexMod")
+	testza.AssertTrue(t, errors.Is(err, ErrNotRecorded))
+
+	_, err = ReadProviderSnapshot(bytes.NewBufferString(`{"version": 2}`))
+	testza.AssertEqual(t, "unsupported provider snapshot version 2", err.Error())
+}
@@ -2,7 +2,9 @@ package resolver
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"maps"
 	"slices"
 
@@ -13,12 +15,27 @@ import (
 )
 
 type ficsitAPISource struct {
+	ctx             context.Context
 	provider        Provider
 	lockfile        *LockFile
 	toInstall       map[string]semver.Constraint
 	requiredTargets map[TargetName]bool
 	modVersionInfo  *xsync.MapOf[string, []ModVersion]
+	prefetchErrors  map[string]error
 	gameVersion     semver.Version
+	hooks           ResolveHooks
+	logger          *slog.Logger
+	strategy        VersionStrategy
+	blocklist       blocklist
+	overrides       overrides
+
+	prereleasePolicy    PrereleasePolicy
+	prereleaseOverrides map[string]PrereleasePolicy
+
+	// unlocked mods ignore their lockfile version
+	unlocked map[string]bool
+	// pinLocked restricts the mods that are not unlocked to their lockfile version
+	pinLocked bool
 }
 
 var clientTargets = map[TargetName]bool{
@@ -41,12 +58,22 @@ func (f *ficsitAPISource) GetPackageVersions(pkg string) ([]pubgrub.PackageVersi
 		return []pubgrub.PackageVersion{{Version: f.gameVersion}}, nil
 	}
 
-	response, err := f.provider.ModVersionsWithDependencies(context.TODO(), pkg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch mod %s: %w", pkg, err)
+	// The versions might have already been prefetched
+	response, ok := f.modVersionInfo.Load(pkg)
+	if !ok {
+		// A failed prefetch is not retried, the provider already failed for this mod
+		if err, failed := f.prefetchErrors[pkg]; failed {
+			return nil, f.providerError(pkg, err)
+		}
+
+		fetched, err := f.provider.ModVersionsWithDependencies(f.ctx, pkg)
+		if err != nil {
+			return nil, f.providerError(pkg, err)
+		}
+		response = f.storeModVersions(pkg, fetched)
 	}
 
-	f.modVersionInfo.Store(pkg, response)
+	pinned, isPinned := f.pinnedVersion(pkg, response)
 
 	versions := make([]pubgrub.PackageVersion, 0)
 	for _, modVersion := range response {
@@ -55,6 +82,18 @@ func (f *ficsitAPISource) GetPackageVersions(pkg string) ([]pubgrub.PackageVersi
 			return nil, fmt.Errorf("failed to parse version %s: %w", modVersion.Version, err)
 		}
 
+		if isPinned && v.Compare(pinned) != 0 {
+			continue
+		}
+
+		if v.IsPrerelease() && !f.allowsPrerelease(pkg, v) {
+			continue
+		}
+
+		if f.blocklist.isBlocked(pkg, v) {
+			continue
+		}
+
 		matches, err := f.matchesTargetRequirements(modVersion)
 		if err != nil {
 			return nil, err
@@ -72,9 +111,38 @@ func (f *ficsitAPISource) GetPackageVersions(pkg string) ([]pubgrub.PackageVersi
 			}
 
 			if dependency.Optional {
-				optionalDependencies[dependency.ModID] = c
+				intersectDependency(optionalDependencies, dependency.ModID, c)
 			} else {
-				dependencies[dependency.ModID] = c
+				intersectDependency(dependencies, dependency.ModID, c)
+			}
+		}
+
+		// A mod that is both a required and an optional dependency is required with both constraints
+		for modID, c := range optionalDependencies {
+			if _, ok := dependencies[modID]; ok {
+				intersectDependency(dependencies, modID, c)
+				delete(optionalDependencies, modID)
+			}
+		}
+
+		// An incompatibility is an optional dependency on every version outside the incompatible range
+		for _, incompatibility := range modVersion.Incompatibilities {
+			condition := incompatibility.Condition
+			if condition == "" {
+				condition = "*"
+			}
+			c, err := semver.NewConstraint(condition)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse constraint %s: %w", incompatibility.Condition, err)
+			}
+
+			allowed := c.Inverse()
+			if existing, ok := dependencies[incompatibility.ModID]; ok {
+				dependencies[incompatibility.ModID] = existing.Intersect(allowed)
+			} else if existing, ok := optionalDependencies[incompatibility.ModID]; ok {
+				optionalDependencies[incompatibility.ModID] = existing.Intersect(allowed)
+			} else {
+				optionalDependencies[incompatibility.ModID] = allowed
 			}
 		}
 
@@ -98,21 +166,113 @@ func (f *ficsitAPISource) GetPackageVersions(pkg string) ([]pubgrub.PackageVersi
 	return versions, nil
 }
 
+func (f *ficsitAPISource) providerError(pkg string, err error) *ProviderError {
+	providerErr := &ProviderError{ModID: pkg, Err: err}
+	if searchable, ok := f.provider.(SearchableProvider); ok && errors.Is(err, ErrModNotFound) {
+		providerErr.Suggestions = suggestMods(f.ctx, searchable, pkg)
+	}
+	return providerErr
+}
+
+// intersectDependency adds a dependency constraint, keeping any constraint already present for the mod
+func intersectDependency(dependencies map[string]semver.Constraint, modID string, c semver.Constraint) {
+	if existing, ok := dependencies[modID]; ok {
+		c = existing.Intersect(c)
+	}
+	dependencies[modID] = c
+}
+
+func (f *ficsitAPISource) storeModVersions(pkg string, response []ModVersion) []ModVersion {
+	response = f.overrides.apply(response)
+
+	f.modVersionInfo.Store(pkg, response)
+
+	f.logger.Debug("fetched mod versions", slog.String("mod", pkg), slog.Int("versions", len(response)))
+	if f.hooks.OnModVersions != nil {
+		f.hooks.OnModVersions(pkg, response)
+	}
+
+	return response
+}
+
 func (f *ficsitAPISource) PickVersion(pkg string, versions []semver.Version) semver.Version {
-	if f.lockfile != nil {
-		if existing, ok := f.lockfile.Mods[pkg]; ok {
-			v, err := semver.NewVersion(existing.Version)
-			if err == nil {
-				if slices.ContainsFunc(versions, func(version semver.Version) bool {
-					return v.Compare(version) == 0
-				}) {
-					return v
-				}
-			}
+	v := f.pickVersion(pkg, versions)
+	if pkg != rootPkg && pkg != factoryGamePkg {
+		f.logger.Debug("picked mod version", slog.String("mod", pkg), slog.String("version", v.String()))
+		if f.hooks.OnVersionPicked != nil {
+			f.hooks.OnVersionPicked(pkg, v.String())
+		}
+	}
+	return v
+}
+
+func (f *ficsitAPISource) pickVersion(pkg string, versions []semver.Version) semver.Version {
+	if pkg == rootPkg || pkg == factoryGamePkg {
+		return helpers.StandardVersionPriority(versions)
+	}
+
+	v, ok := f.lockedVersion(pkg)
+	if ok {
+		if slices.ContainsFunc(versions, func(version semver.Version) bool {
+			return v.Compare(version) == 0
+		}) {
+			return v
+		}
+	}
+
+	var locked *semver.Version
+	if ok {
+		locked = &v
+	}
+
+	return f.strategy.PickVersion(pkg, versions, locked)
+}
+
+func (f *ficsitAPISource) lockedVersion(pkg string) (semver.Version, bool) {
+	if f.lockfile == nil || f.unlocked[pkg] {
+		return semver.Version{}, false
+	}
+	existing, ok := f.lockfile.Mods[pkg]
+	if !ok {
+		return semver.Version{}, false
+	}
+	v, err := semver.NewVersion(existing.Version)
+	if err != nil {
+		return semver.Version{}, false
+	}
+	return v, true
+}
+
+// pinnedVersion returns the version a mod is restricted to, if the locked version is still available
+func (f *ficsitAPISource) pinnedVersion(pkg string, modVersions []ModVersion) (semver.Version, bool) {
+	if !f.pinLocked {
+		return semver.Version{}, false
+	}
+	locked, ok := f.lockedVersion(pkg)
+	if !ok {
+		return semver.Version{}, false
+	}
+	for _, modVersion := range modVersions {
+		v, err := semver.NewVersion(modVersion.Version)
+		if err == nil && v.Compare(locked) == 0 {
+			return locked, true
 		}
 	}
+	return semver.Version{}, false
+}
+
+// contextSource stops the solver at the next decision once ctx is done,
+// since pubgrub itself has no notion of cancellation
+type contextSource struct {
+	pubgrub.Source
+	ctx context.Context
+}
 
-	return helpers.StandardVersionPriority(versions)
+func (c *contextSource) GetPackageVersions(pkg string) ([]pubgrub.PackageVersion, error) {
+	if err := c.ctx.Err(); err != nil {
+		return nil, fmt.Errorf("resolution cancelled: %w", err)
+	}
+	return c.Source.GetPackageVersions(pkg) //nolint:wrapcheck
 }
 
 func (f *ficsitAPISource) matchesTargetRequirements(modVersion ModVersion) (bool, error) {
@@ -0,0 +1,117 @@
+package resolver
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/helpers"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+// VersionStrategy picks the version of a mod the solver should try next.
+// The lockfile version is always preferred while it is still allowed,
+// the strategy is only asked when the mod is not locked, unlocked, or its locked version is not allowed.
+type VersionStrategy interface {
+	// PickVersion must return one of versions, which are sorted in increasing order and never empty.
+	// locked is the lockfile version of the mod, or nil if there is none
+	PickVersion(modID string, versions []semver.Version, locked *semver.Version) semver.Version
+}
+
+var (
+	// NewestVersionStrategy picks the latest release, or the latest pre-release if there are no releases
+	NewestVersionStrategy VersionStrategy = newestVersionStrategy{}
+
+	// OldestVersionStrategy picks the oldest release, or the oldest pre-release if there are no releases.
+	// Useful for testing that the lower bounds of dependency constraints are correct
+	OldestVersionStrategy VersionStrategy = oldestVersionStrategy{}
+
+	// ClosestToLockedVersionStrategy picks the version that is the smallest change from the locked version,
+	// comparing the nearest newer and nearest older versions by their major, then minor, then patch distance,
+	// and preferring the newer one on a tie. Pre-releases are only picked if there are no releases
+	ClosestToLockedVersionStrategy VersionStrategy = closestToLockedVersionStrategy{}
+)
+
+type newestVersionStrategy struct{}
+
+func (newestVersionStrategy) PickVersion(_ string, versions []semver.Version, _ *semver.Version) semver.Version {
+	return helpers.StandardVersionPriority(versions)
+}
+
+type oldestVersionStrategy struct{}
+
+func (oldestVersionStrategy) PickVersion(_ string, versions []semver.Version, _ *semver.Version) semver.Version {
+	for _, v := range versions {
+		if !v.IsPrerelease() {
+			return v
+		}
+	}
+	return versions[0]
+}
+
+type closestToLockedVersionStrategy struct{}
+
+func (closestToLockedVersionStrategy) PickVersion(modID string, versions []semver.Version, locked *semver.Version) semver.Version {
+	if locked == nil {
+		return NewestVersionStrategy.PickVersion(modID, versions, locked)
+	}
+
+	candidates := releases(versions)
+	if len(candidates) == 0 {
+		candidates = versions
+	}
+
+	var older, newer *semver.Version
+	for i := range candidates {
+		if candidates[i].Compare(*locked) >= 0 {
+			newer = &candidates[i]
+			break
+		}
+		older = &candidates[i]
+	}
+
+	switch {
+	case newer == nil:
+		return *older
+	case older == nil:
+		return *newer
+	}
+
+	olderDistance := versionDistance(*older, *locked)
+	newerDistance := versionDistance(*newer, *locked)
+	if slices.Compare(olderDistance[:], newerDistance[:]) < 0 {
+		return *older
+	}
+	return *newer
+}
+
+func releases(versions []semver.Version) []semver.Version {
+	var result []semver.Version
+	for _, v := range versions {
+		if !v.IsPrerelease() {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// versionDistance returns the absolute difference of the major, minor and patch of two versions
+func versionDistance(a semver.Version, b semver.Version) [3]int {
+	aParts := versionParts(a)
+	bParts := versionParts(b)
+
+	var distance [3]int
+	for i := range distance {
+		distance[i] = aParts[i] - bParts[i]
+		if distance[i] < 0 {
+			distance[i] = -distance[i]
+		}
+	}
+	return distance
+}
+
+func versionParts(v semver.Version) [3]int {
+	var parts [3]int
+	// The version does not expose its parts, but always formats them first
+	_, _ = fmt.Sscanf(v.String(), "%d.%d.%d", &parts[0], &parts[1], &parts[2])
+	return parts
+}
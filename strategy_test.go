@@ -0,0 +1,70 @@
+package resolver
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+)
+
+func mustVersions(t *testing.T, versions ...string) []semver.Version {
+	t.Helper()
+	result := make([]semver.Version, 0, len(versions))
+	for _, v := range versions {
+		parsed, err := semver.NewVersion(v)
+		testza.AssertNoError(t, err)
+		result = append(result, parsed)
+	}
+	return result
+}
+
+func TestVersionStrategies(t *testing.T) {
+	versions := mustVersions(t, "1.0.0-beta.1", "1.0.0", "1.1.0", "2.0.0", "2.1.0-rc.1")
+	locked := mustVersions(t, "1.0.5")[0]
+
+	testza.AssertEqual(t, "2.0.0", NewestVersionStrategy.PickVersion("Mod", versions, nil).String())
+	testza.AssertEqual(t, "1.0.0", OldestVersionStrategy.PickVersion("Mod", versions, nil).String())
+	testza.AssertEqual(t, "1.0.0", ClosestToLockedVersionStrategy.PickVersion("Mod", versions, &locked).String())
+	testza.AssertEqual(t, "2.0.0", ClosestToLockedVersionStrategy.PickVersion("Mod", versions, nil).String())
+
+	locked = mustVersions(t, "3.0.0")[0]
+	testza.AssertEqual(t, "2.0.0", ClosestToLockedVersionStrategy.PickVersion("Mod", versions, &locked).String())
+
+	locked = mustVersions(t, "1.0.5")[0]
+	testza.AssertEqual(t, "1.0.4", ClosestToLockedVersionStrategy.PickVersion("Mod", mustVersions(t, "1.0.4", "3.0.0"), &locked).String())
+	testza.AssertEqual(t, "1.0.6", ClosestToLockedVersionStrategy.PickVersion("Mod", mustVersions(t, "1.0.6-rc.1", "1.0.6"), &locked).String())
+	testza.AssertEqual(t, "1.0.6", ClosestToLockedVersionStrategy.PickVersion("Mod", mustVersions(t, "1.0.4", "1.0.6"), &locked).String())
+	testza.AssertEqual(t, "1.0.6-rc.1", ClosestToLockedVersionStrategy.PickVersion("Mod", mustVersions(t, "1.0.4-rc.1", "1.0.6-rc.1"), &locked).String())
+}
+
+func TestResolveOldestVersionStrategy(t *testing.T) {
+	resolver := NewDependencyResolver(MockProvider{})
+
+	result, err := resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": "*",
+	}, WithGameVersion(math.MaxInt), WithVersionStrategy(OldestVersionStrategy)))
+
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.2.10", result.LockFile.Mods["RefinedPower"].Version)
+	testza.AssertEqual(t, "2.1.10", result.LockFile.Mods["ModularUI"].Version)
+	testza.AssertEqual(t, "1.1.5", result.LockFile.Mods["RefinedRDLib"].Version)
+	testza.AssertEqual(t, "3.6.0", result.LockFile.Mods["SML"].Version)
+}
+
+func TestResolveClosestToLockedVersionStrategy(t *testing.T) {
+	resolver := NewDependencyResolver(MockProvider{})
+
+	lockfile := NewLockfile()
+	lockfile.Mods["RefinedPower"] = LockedMod{
+		Version: "3.2.10",
+	}
+
+	result, err := resolver.Resolve(context.Background(), NewResolveRequest(map[string]string{
+		"RefinedPower": ">3.2.10",
+	}, WithLockFile(lockfile), WithGameVersion(math.MaxInt), WithVersionStrategy(ClosestToLockedVersionStrategy)))
+
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.2.11", result.LockFile.Mods["RefinedPower"].Version)
+}
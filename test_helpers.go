@@ -2,13 +2,34 @@ package resolver
 
 import (
 	"context"
-	"errors"
 )
 
-var _ Provider = (*MockProvider)(nil)
+var _ SearchableProvider = (*MockProvider)(nil)
 
 type MockProvider struct{}
 
+// staticProvider serves fixed versions and names, and returns ErrModNotFound for any other mod
+type staticProvider struct {
+	versions map[string][]ModVersion
+	names    map[string]ModName
+}
+
+func (p staticProvider) ModVersionsWithDependencies(_ context.Context, modID string) ([]ModVersion, error) {
+	versions, ok := p.versions[modID]
+	if !ok {
+		return nil, ErrModNotFound
+	}
+	return versions, nil
+}
+
+func (p staticProvider) GetModName(_ context.Context, modReference string) (*ModName, error) {
+	name, ok := p.names[modReference]
+	if !ok {
+		return nil, ErrModNotFound
+	}
+	return &name, nil
+}
+
 var commonTargets = []Target{
 	{
 		TargetName: "Windows",
@@ -147,8 +168,8 @@ func (m MockProvider) ModVersionsWithDependencies(_ context.Context, modID strin
 				Targets:          commonTargets,
 			},
 		}, nil
-	case "ThisModDoesNotExist$$$":
-		return []ModVersion{}, errors.New("mod not found")
+	case "ThisModDoesNotExist$$$", "RefinedPowr":
+		return []ModVersion{}, ErrModNotFound
 	case "ComplexMod":
 		return []ModVersion{
 			{
@@ -238,6 +259,21 @@ func (m MockProvider) ModVersionsWithDependencies(_ context.Context, modID strin
 				},
 			},
 		}, nil
+	case "MapOverhaul":
+		return []ModVersion{
+			{
+				Version:          "1.0.0",
+				RequiredOnRemote: true,
+				Dependencies:     []Dependency{sml3},
+				Incompatibilities: []Incompatibility{
+					{
+						ModID:     "ComplexMod",
+						Condition: ">=2.0.0",
+					},
+				},
+				Targets: commonTargets,
+			},
+		}, nil
 	case "ClientOnlyMod":
 		return []ModVersion{
 			{
@@ -297,12 +333,24 @@ func (m MockProvider) GetModName(_ context.Context, modReference string) (*ModNa
 			ModReference: "RefinedRDLib",
 			Name:         "RefinedRDLib",
 		}, nil
+	case "ModularUI":
+		return &ModName{
+			ID:           "ModularUI",
+			ModReference: "ModularUI",
+			Name:         "Modular UI",
+		}, nil
 	case "ComplexMod":
 		return &ModName{
 			ID:           "asd32rfewqhy4",
 			ModReference: "ComplexMod",
 			Name:         "ComplexMod",
 		}, nil
+	case "MapOverhaul":
+		return &ModName{
+			ID:           "MapOverhaul",
+			ModReference: "MapOverhaul",
+			Name:         "Map Overhaul",
+		}, nil
 	case "ClientOnlyMod":
 		return &ModName{
 			ID:           "asd32rfewqhy4",
@@ -325,3 +373,16 @@ func (m MockProvider) GetModName(_ context.Context, modReference string) (*ModNa
 
 	panic("GetModName: " + modReference)
 }
+
+func (m MockProvider) SearchMods(ctx context.Context, _ string) ([]ModName, error) {
+	modReferences := []string{"RefinedPower", "RefinedRDLib", "ModularUI", "ComplexMod", "MapOverhaul", "ClientOnlyMod", "ServerOnlyMod", "SML"}
+	mods := make([]ModName, 0, len(modReferences))
+	for _, modReference := range modReferences {
+		name, err := m.GetModName(ctx, modReference)
+		if err != nil {
+			return nil, err
+		}
+		mods = append(mods, *name)
+	}
+	return mods, nil
+}
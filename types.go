@@ -28,11 +28,12 @@ type VersionDependency struct {
 }
 
 type ModVersion struct {
-	Version          string       `json:"version"`
-	GameVersion      string       `json:"game_version"`
-	Dependencies     []Dependency `json:"dependencies"`
-	Targets          []Target     `json:"targets"`
-	RequiredOnRemote bool         `json:"required_on_remote"`
+	Version           string            `json:"version"`
+	GameVersion       string            `json:"game_version"`
+	Dependencies      []Dependency      `json:"dependencies"`
+	Incompatibilities []Incompatibility `json:"incompatibilities,omitempty"`
+	Targets           []Target          `json:"targets"`
+	RequiredOnRemote  bool              `json:"required_on_remote"`
 }
 
 type Dependency struct {
@@ -41,6 +42,13 @@ type Dependency struct {
 	Optional  bool   `json:"optional"`
 }
 
+// Incompatibility declares that a mod cannot be installed alongside the versions of ModID matching Condition.
+// An empty condition matches every version
+type Incompatibility struct {
+	ModID     string `json:"mod_id"`
+	Condition string `json:"condition"`
+}
+
 type Target struct {
 	TargetName TargetName `json:"target_name"`
 	Link       string     `json:"link"`
@@ -0,0 +1,201 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var _ Provider = (*UPluginProvider)(nil)
+
+// UPluginProvider is a Provider for the mods unpacked in a directory, such as an installed FactoryGame/Mods folder,
+// which reads the metadata of each mod from its <mod reference>/<mod reference>.uplugin file.
+// Every mod has a single version, the one on disk.
+// A malformed .uplugin file only fails the lookups of its own mod
+type UPluginProvider struct {
+	dir     string
+	targets []TargetName
+
+	once    sync.Once
+	plugins map[string]uPluginMod
+	invalid map[string]error
+	err     error
+}
+
+type uPlugin struct {
+	VersionName      string `json:"VersionName"`
+	SemVersion       string `json:"SemVersion"`
+	FriendlyName     string `json:"FriendlyName"`
+	GameVersion      string `json:"GameVersion"`
+	RequiredOnRemote *bool  `json:"RequiredOnRemote"`
+	Plugins          []struct {
+		Name       string `json:"Name"`
+		SemVersion string `json:"SemVersion"`
+		Optional   bool   `json:"Optional"`
+	} `json:"Plugins"`
+}
+
+type uPluginMod struct {
+	name    ModName
+	version ModVersion
+}
+
+// NewUPluginProvider creates a provider for the plugins in dir.
+// The files on disk do not say which targets they were built for, so every mod is considered to have the given targets
+func NewUPluginProvider(dir string, targets ...TargetName) *UPluginProvider {
+	return &UPluginProvider{
+		dir:     dir,
+		targets: targets,
+	}
+}
+
+func (u *UPluginProvider) ModVersionsWithDependencies(_ context.Context, modID string) ([]ModVersion, error) {
+	mod, err := u.get(modID)
+	if err != nil {
+		return nil, err
+	}
+	return []ModVersion{mod.version}, nil
+}
+
+func (u *UPluginProvider) GetModName(_ context.Context, modReference string) (*ModName, error) {
+	mod, err := u.get(modReference)
+	if err != nil {
+		return nil, err
+	}
+	return &mod.name, nil
+}
+
+func (u *UPluginProvider) get(modReference string) (*uPluginMod, error) {
+	u.once.Do(func() {
+		u.plugins, u.invalid, u.err = u.scan()
+	})
+	if u.err != nil {
+		return nil, u.err
+	}
+	if err, ok := u.invalid[modReference]; ok {
+		return nil, err
+	}
+
+	mod, ok := u.plugins[modReference]
+	if !ok {
+		return nil, ErrModNotFound
+	}
+	return &mod, nil
+}
+
+func (u *UPluginProvider) scan() (map[string]uPluginMod, map[string]error, error) {
+	entries, err := os.ReadDir(u.dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+
+	plugins := make(map[string]uPluginMod)
+	invalid := make(map[string]error)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		modReference := entry.Name()
+		path := filepath.Join(u.dir, modReference, modReference+".uplugin")
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		mod, err := u.readPlugin(modReference, path)
+		if err != nil {
+			invalid[modReference] = err
+			continue
+		}
+		plugins[modReference] = *mod
+	}
+
+	// Dependencies without a SemVersion are on any version of a mod, unless they are engine plugins, which are always available
+	for modReference, mod := range plugins {
+		dependencies := make([]Dependency, 0, len(mod.version.Dependencies))
+		for _, dependency := range mod.version.Dependencies {
+			if dependency.Condition == "" {
+				_, isPlugin := plugins[dependency.ModID]
+				_, isInvalidPlugin := invalid[dependency.ModID]
+				if !isPlugin && !isInvalidPlugin {
+					continue
+				}
+				dependency.Condition = "*"
+			}
+			dependencies = append(dependencies, dependency)
+		}
+		if len(dependencies) == 0 {
+			dependencies = nil
+		}
+		mod.version.Dependencies = dependencies
+		plugins[modReference] = mod
+	}
+
+	return plugins, invalid, nil
+}
+
+func (u *UPluginProvider) readPlugin(modReference string, path string) (*uPluginMod, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin %s: %w", path, err)
+	}
+
+	// Unreal writes the files with a byte order mark
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	var plugin uPlugin
+	if err := json.Unmarshal(data, &plugin); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin %s: %w", path, err)
+	}
+
+	version := plugin.SemVersion
+	if version == "" {
+		version = plugin.VersionName
+	}
+	if version == "" {
+		return nil, fmt.Errorf("plugin %s has no version", path)
+	}
+
+	var dependencies []Dependency
+	for _, dependency := range plugin.Plugins {
+		dependencies = append(dependencies, Dependency{
+			ModID:     dependency.Name,
+			Condition: dependency.SemVersion,
+			Optional:  dependency.Optional,
+		})
+	}
+
+	targets := make([]Target, 0, len(u.targets))
+	for _, target := range u.targets {
+		targets = append(targets, Target{TargetName: target})
+	}
+
+	requiredOnRemote := true
+	if plugin.RequiredOnRemote != nil {
+		requiredOnRemote = *plugin.RequiredOnRemote
+	}
+
+	name := plugin.FriendlyName
+	if name == "" {
+		name = modReference
+	}
+
+	return &uPluginMod{
+		name: ModName{
+			ID:           modReference,
+			ModReference: modReference,
+			Name:         name,
+		},
+		version: ModVersion{
+			Version:          version,
+			GameVersion:      plugin.GameVersion,
+			Dependencies:     dependencies,
+			Targets:          targets,
+			RequiredOnRemote: requiredOnRemote,
+		},
+	}, nil
+}
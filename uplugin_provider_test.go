@@ -0,0 +1,73 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+func TestUPluginProvider(t *testing.T) {
+	provider := NewUPluginProvider(filepath.Join("testdata", "uplugin"), TargetNameWindows)
+
+	versions, err := provider.ModVersionsWithDependencies(context.Background(), "RefinedPower")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, []ModVersion{
+		{
+			Version: "3.2.13",
+			Dependencies: []Dependency{
+				{ModID: "SML", Condition: "^3.6.0"},
+				{ModID: "ModularUI", Condition: "^2.1.11", Optional: true},
+			},
+			Targets:          []Target{{TargetName: TargetNameWindows}},
+			RequiredOnRemote: true,
+		},
+	}, versions)
+
+	name, err := provider.GetModName(context.Background(), "SML")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "Satisfactory Mod Loader", name.Name)
+
+	_, err = provider.ModVersionsWithDependencies(context.Background(), "NotAMod")
+	testza.AssertEqual(t, "mod not found", err.Error())
+	testza.AssertTrue(t, errors.Is(err, ErrModNotFound))
+
+	resolved, err := NewDependencyResolver(provider).ResolveModDependencies(map[string]string{
+		"RefinedPower": "*",
+	}, nil, math.MaxInt, []TargetName{TargetNameWindows})
+	testza.AssertNoError(t, err)
+	testza.AssertLen(t, resolved.Mods, 2)
+	testza.AssertEqual(t, "3.6.1", resolved.Mods["SML"].Version)
+}
+
+func TestUPluginProviderDependencyWithoutVersion(t *testing.T) {
+	provider := NewUPluginProvider(filepath.Join("testdata", "uplugin"), TargetNameWindows)
+
+	versions, err := provider.ModVersionsWithDependencies(context.Background(), "PowerAddon")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, []Dependency{
+		{ModID: "RefinedPower", Condition: "*"},
+	}, versions[0].Dependencies)
+
+	versions, err = provider.ModVersionsWithDependencies(context.Background(), "SML")
+	testza.AssertNoError(t, err)
+	testza.AssertNil(t, versions[0].Dependencies)
+}
+
+func TestUPluginProviderMalformedPlugin(t *testing.T) {
+	provider := NewUPluginProvider(filepath.Join("testdata", "uplugin"), TargetNameWindows)
+
+	_, err := provider.ModVersionsWithDependencies(context.Background(), "Broken")
+	testza.AssertNotNil(t, err)
+	testza.AssertContains(t, err.Error(), filepath.Join("testdata", "uplugin", "Broken", "Broken.uplugin"))
+
+	_, err = provider.GetModName(context.Background(), "Broken")
+	testza.AssertNotNil(t, err)
+
+	versions, err := provider.ModVersionsWithDependencies(context.Background(), "SML")
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, "3.6.1", versions[0].Version)
+}
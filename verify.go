@@ -0,0 +1,237 @@
+package resolver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+type ArtifactStatus string
+
+const (
+	ArtifactOK ArtifactStatus = "ok"
+
+	// ArtifactMissing is a locked mod without an artifact
+	ArtifactMissing ArtifactStatus = "missing"
+
+	// ArtifactMismatch is an artifact whose SHA-256 is not the locked hash
+	ArtifactMismatch ArtifactStatus = "mismatch"
+
+	// ArtifactExtra is an artifact that does not belong to any locked mod
+	ArtifactExtra ArtifactStatus = "extra"
+
+	// ArtifactUnverifiable is an artifact whose locked target has no hash to check it against,
+	// such as those of local plugins or of migrated initial lockfiles,
+	// or an artifact that is an unpacked mod directory, since the locked hash is that of the zip
+	ArtifactUnverifiable ArtifactStatus = "unverifiable"
+)
+
+type ArtifactResult struct {
+	Status ArtifactStatus `json:"status"`
+
+	// ModReference and Version are empty for extra artifacts
+	ModReference string `json:"mod_reference,omitempty"`
+	Version      string `json:"version,omitempty"`
+
+	Path         string `json:"path"`
+	ExpectedHash string `json:"expected_hash,omitempty"`
+	ActualHash   string `json:"actual_hash,omitempty"`
+}
+
+type VerifyReport struct {
+	Target TargetName `json:"target"`
+
+	// Results contains one result per locked mod, followed by the extra artifacts, sorted by path
+	Results []ArtifactResult `json:"results"`
+}
+
+type VerifyOptions struct {
+	// ArtifactPath returns the path of the artifact of a mod, defaults to DefaultArtifactPath
+	ArtifactPath func(modReference string, version string, target TargetName) string
+
+	// IsArtifact decides which files not belonging to a locked mod are reported as extra,
+	// defaults to the files named like DefaultArtifactPath for the verified target
+	IsArtifact func(path string, target TargetName) bool
+
+	// IsArtifactDir decides which directories not belonging to a locked mod are reported as extra,
+	// such as the mod folders of an installed mods directory. By default no directory is
+	IsArtifactDir func(path string, target TargetName) bool
+}
+
+// DefaultArtifactPath is the layout of the download cache, <mod reference>_<version>_<target>.zip
+func DefaultArtifactPath(modReference string, version string, target TargetName) string {
+	return fmt.Sprintf("%s_%s_%s.zip", modReference, version, target)
+}
+
+func isDefaultArtifact(filePath string, target TargetName) bool {
+	return path.Dir(filePath) == "." && strings.HasSuffix(filePath, "_"+string(target)+".zip")
+}
+
+// VerifyLockfile checks the artifacts of the given target in fsys against the hashes in the lockfile.
+// Artifacts can be zips, such as in the download cache, or unpacked mod directories, such as in an installed mods directory.
+// Locked mods that have no build for the target are skipped, since they are not installed on it
+func VerifyLockfile(ctx context.Context, lockFile *LockFile, target TargetName, fsys fs.FS, options VerifyOptions) (*VerifyReport, error) {
+	if options.ArtifactPath == nil {
+		options.ArtifactPath = DefaultArtifactPath
+	}
+	if options.IsArtifact == nil {
+		options.IsArtifact = isDefaultArtifact
+	}
+
+	report := &VerifyReport{
+		Target:  target,
+		Results: make([]ArtifactResult, 0, len(lockFile.Mods)),
+	}
+	expected := make(map[string]bool, len(lockFile.Mods))
+
+	for modReference, mod := range lockFile.Mods {
+		lockedTarget, ok := mod.Targets[string(target)]
+		if !ok {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("verification cancelled: %w", err)
+		}
+
+		artifactPath := options.ArtifactPath(modReference, mod.Version, target)
+		expected[artifactPath] = true
+
+		result := ArtifactResult{
+			ModReference: modReference,
+			Version:      mod.Version,
+			Path:         artifactPath,
+			ExpectedHash: lockedTarget.Hash,
+		}
+
+		info, err := fs.Stat(fsys, artifactPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			result.Status = ArtifactMissing
+			report.Results = append(report.Results, result)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat artifact %s: %w", artifactPath, err)
+		}
+		if info.IsDir() {
+			result.Status = ArtifactUnverifiable
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		hash, err := hashArtifact(fsys, artifactPath)
+		switch {
+		case err != nil:
+			return nil, err
+		case lockedTarget.Hash == "":
+			result.Status = ArtifactUnverifiable
+			result.ActualHash = hash
+		case strings.EqualFold(hash, lockedTarget.Hash):
+			result.Status = ArtifactOK
+			result.ActualHash = hash
+		default:
+			result.Status = ArtifactMismatch
+			result.ActualHash = hash
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	slices.SortFunc(report.Results, func(a, b ArtifactResult) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	var extra []ArtifactResult
+	err := fs.WalkDir(fsys, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			switch {
+			case filePath == ".":
+				return nil
+			case expected[filePath]:
+				return fs.SkipDir
+			case options.IsArtifactDir != nil && options.IsArtifactDir(filePath, target):
+				extra = append(extra, ArtifactResult{
+					Status: ArtifactExtra,
+					Path:   filePath,
+				})
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if expected[filePath] || !options.IsArtifact(filePath, target) {
+			return nil
+		}
+		extra = append(extra, ArtifactResult{
+			Status: ArtifactExtra,
+			Path:   filePath,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+	report.Results = append(report.Results, extra...)
+
+	return report, nil
+}
+
+func hashArtifact(fsys fs.FS, artifactPath string) (string, error) {
+	f, err := fsys.Open(artifactPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open artifact %s: %w", artifactPath, err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("failed to read artifact %s: %w", artifactPath, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// OK reports whether every artifact is present with the locked hash, and there are no extra artifacts.
+// Unverifiable artifacts do not fail the verification, since they are present
+func (r *VerifyReport) OK() bool {
+	for _, result := range r.Results {
+		if result.Status != ArtifactOK && result.Status != ArtifactUnverifiable {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *VerifyReport) Missing() []ArtifactResult {
+	return r.withStatus(ArtifactMissing)
+}
+
+func (r *VerifyReport) Mismatched() []ArtifactResult {
+	return r.withStatus(ArtifactMismatch)
+}
+
+func (r *VerifyReport) Extra() []ArtifactResult {
+	return r.withStatus(ArtifactExtra)
+}
+
+func (r *VerifyReport) Unverifiable() []ArtifactResult {
+	return r.withStatus(ArtifactUnverifiable)
+}
+
+func (r *VerifyReport) withStatus(status ArtifactStatus) []ArtifactResult {
+	var results []ArtifactResult
+	for _, result := range r.Results {
+		if result.Status == status {
+			results = append(results, result)
+		}
+	}
+	return results
+}
@@ -0,0 +1,152 @@
+package resolver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MarvinJWendt/testza"
+)
+
+func sha256Hex(data string) string {
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestVerifyLockfile(t *testing.T) {
+	lockFile := NewLockfile()
+	lockFile.Mods["SML"] = LockedMod{Version: "3.6.1", Targets: map[string]LockedModTarget{
+		"Windows":     {Hash: sha256Hex("sml windows")},
+		"LinuxServer": {Hash: sha256Hex("sml linux")},
+	}}
+	lockFile.Mods["RefinedPower"] = LockedMod{Version: "3.2.13", Targets: map[string]LockedModTarget{
+		"Windows": {Hash: sha256Hex("refined power")},
+	}}
+	lockFile.Mods["ModularUI"] = LockedMod{Version: "2.1.11", Targets: map[string]LockedModTarget{
+		"Windows": {Hash: sha256Hex("modular ui")},
+	}}
+	lockFile.Mods["ServerOnlyMod"] = LockedMod{Version: "1.0.0", Targets: map[string]LockedModTarget{
+		"LinuxServer": {Hash: sha256Hex("server only")},
+	}}
+
+	fsys := fstest.MapFS{
+		"SML_3.6.1_Windows.zip":           {Data: []byte("sml windows")},
+		"SML_3.6.1_LinuxServer.zip":       {Data: []byte("sml linux")},
+		"RefinedPower_3.2.13_Windows.zip": {Data: []byte("tampered")},
+		"RefinedPower_3.2.10_Windows.zip": {Data: []byte("old refined power")},
+		"notes.txt":                       {Data: []byte("not an artifact")},
+	}
+
+	report, err := VerifyLockfile(context.Background(), lockFile, TargetNameWindows, fsys, VerifyOptions{})
+	testza.AssertNoError(t, err)
+	testza.AssertFalse(t, report.OK())
+	testza.AssertEqual(t, []ArtifactResult{
+		{Status: ArtifactMissing, ModReference: "ModularUI", Version: "2.1.11", Path: "ModularUI_2.1.11_Windows.zip", ExpectedHash: sha256Hex("modular ui")},
+		{Status: ArtifactMismatch, ModReference: "RefinedPower", Version: "3.2.13", Path: "RefinedPower_3.2.13_Windows.zip", ExpectedHash: sha256Hex("refined power"), ActualHash: sha256Hex("tampered")},
+		{Status: ArtifactOK, ModReference: "SML", Version: "3.6.1", Path: "SML_3.6.1_Windows.zip", ExpectedHash: sha256Hex("sml windows"), ActualHash: sha256Hex("sml windows")},
+		{Status: ArtifactExtra, Path: "RefinedPower_3.2.10_Windows.zip"},
+	}, report.Results)
+	testza.AssertLen(t, report.Missing(), 1)
+	testza.AssertLen(t, report.Mismatched(), 1)
+	testza.AssertLen(t, report.Extra(), 1)
+
+	report, err = VerifyLockfile(context.Background(), lockFile, TargetNameLinuxServer, fsys, VerifyOptions{})
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, []ArtifactResult{
+		{Status: ArtifactOK, ModReference: "SML", Version: "3.6.1", Path: "SML_3.6.1_LinuxServer.zip", ExpectedHash: sha256Hex("sml linux"), ActualHash: sha256Hex("sml linux")},
+		{Status: ArtifactMissing, ModReference: "ServerOnlyMod", Version: "1.0.0", Path: "ServerOnlyMod_1.0.0_LinuxServer.zip", ExpectedHash: sha256Hex("server only")},
+	}, report.Results)
+}
+
+func TestVerifyLockfileCustomLayout(t *testing.T) {
+	lockFile := NewLockfile()
+	lockFile.Mods["SML"] = LockedMod{Version: "3.6.1", Targets: map[string]LockedModTarget{
+		"WindowsServer": {Hash: sha256Hex("sml")},
+	}}
+
+	fsys := fstest.MapFS{
+		"SML/WindowsServer.zip":    {Data: []byte("sml")},
+		"OldMod/WindowsServer.zip": {Data: []byte("old")},
+	}
+
+	report, err := VerifyLockfile(context.Background(), lockFile, TargetNameWindowsServer, fsys, VerifyOptions{
+		ArtifactPath: func(modReference string, _ string, target TargetName) string {
+			return modReference + "/" + string(target) + ".zip"
+		},
+		IsArtifact: func(path string, target TargetName) bool {
+			return true
+		},
+	})
+	testza.AssertNoError(t, err)
+	testza.AssertFalse(t, report.OK())
+	testza.AssertEqual(t, []ArtifactResult{{Status: ArtifactExtra, Path: "OldMod/WindowsServer.zip"}}, report.Extra())
+
+	delete(fsys, "OldMod/WindowsServer.zip")
+	report, err = VerifyLockfile(context.Background(), lockFile, TargetNameWindowsServer, fsys, VerifyOptions{
+		ArtifactPath: func(modReference string, _ string, target TargetName) string {
+			return modReference + "/" + string(target) + ".zip"
+		},
+	})
+	testza.AssertNoError(t, err)
+	testza.AssertTrue(t, report.OK())
+}
+
+func TestVerifyLockfileWithoutHashes(t *testing.T) {
+	lockFile, err := ReadLockfile(strings.NewReader(`{
+		"SML": {"version": "3.6.1", "link": "https://example.com/SML.zip", "dependencies": {}},
+		"RefinedPower": {"version": "3.2.13", "link": "https://example.com/RefinedPower.zip", "dependencies": {}}
+	}`))
+	testza.AssertNoError(t, err)
+
+	fsys := fstest.MapFS{
+		"SML_3.6.1_Windows.zip": {Data: []byte("sml")},
+	}
+
+	report, err := VerifyLockfile(context.Background(), lockFile, TargetNameWindows, fsys, VerifyOptions{})
+	testza.AssertNoError(t, err)
+	testza.AssertEqual(t, []ArtifactResult{
+		{Status: ArtifactMissing, ModReference: "RefinedPower", Version: "3.2.13", Path: "RefinedPower_3.2.13_Windows.zip"},
+		{Status: ArtifactUnverifiable, ModReference: "SML", Version: "3.6.1", Path: "SML_3.6.1_Windows.zip", ActualHash: sha256Hex("sml")},
+	}, report.Results)
+	testza.AssertLen(t, report.Mismatched(), 0)
+	testza.AssertLen(t, report.Unverifiable(), 1)
+
+	delete(lockFile.Mods, "RefinedPower")
+	report, err = VerifyLockfile(context.Background(), lockFile, TargetNameWindows, fsys, VerifyOptions{})
+	testza.AssertNoError(t, err)
+	testza.AssertTrue(t, report.OK())
+}
+
+func TestVerifyLockfileInstalledMods(t *testing.T) {
+	lockFile := NewLockfile()
+	lockFile.Mods["SML"] = LockedMod{Version: "3.6.1", Targets: map[string]LockedModTarget{
+		"Windows": {Hash: sha256Hex("sml")},
+	}}
+	lockFile.Mods["RefinedPower"] = LockedMod{Version: "3.2.13", Targets: map[string]LockedModTarget{
+		"Windows": {Hash: sha256Hex("refined power")},
+	}}
+
+	fsys := fstest.MapFS{
+		"SML/SML.uplugin":       {Data: []byte("{}")},
+		"OldMod/OldMod.uplugin": {Data: []byte("{}")},
+	}
+
+	report, err := VerifyLockfile(context.Background(), lockFile, TargetNameWindows, fsys, VerifyOptions{
+		ArtifactPath: func(modReference string, _ string, _ TargetName) string {
+			return modReference
+		},
+		IsArtifactDir: func(path string, _ TargetName) bool {
+			return !strings.Contains(path, "/")
+		},
+	})
+	testza.AssertNoError(t, err)
+	testza.AssertFalse(t, report.OK())
+	testza.AssertEqual(t, []ArtifactResult{
+		{Status: ArtifactMissing, ModReference: "RefinedPower", Version: "3.2.13", Path: "RefinedPower", ExpectedHash: sha256Hex("refined power")},
+		{Status: ArtifactUnverifiable, ModReference: "SML", Version: "3.6.1", Path: "SML", ExpectedHash: sha256Hex("sml")},
+		{Status: ArtifactExtra, Path: "OldMod"},
+	}, report.Results)
+}